@@ -1,6 +1,7 @@
 package libovsdb
 
 import (
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"errors"
@@ -10,6 +11,7 @@ import (
 	"reflect"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/cenkalti/rpc2"
 	"github.com/cenkalti/rpc2/jsonrpc"
@@ -18,16 +20,102 @@ import (
 // OvsdbClient is an OVSDB client
 type OvsdbClient struct {
 	rpcClient     *rpc2.Client
-	Schema        map[string]DatabaseSchema
+	codec         *clientCodec
+	closed        bool
+	rpcMutex      *sync.RWMutex
+	Schema        map[string]DatabaseSchema // replaced on reconnection, read it with Schemas
+	schemaMutex   *sync.RWMutex
 	handlers      []NotificationHandler
 	handlersMutex *sync.Mutex
+	monitors      []*MonitorHandle
+	monitorsMutex *sync.Mutex
+	endpoints     string
+	tlsConfig     *tls.Config
+	options       ConnectOptions
 }
 
-func newOvsdbClient(c *rpc2.Client) *OvsdbClient {
+// MonitorHandle identifies an active monitor. It records the monitor
+// requests so they can be re-issued on reconnection
+type MonitorHandle struct {
+	ovs          *OvsdbClient
+	database     string
+	jsonContext  interface{}
+	requests     map[string]MonitorRequest
+	condRequests map[string]MonitorCondRequest
+	since        bool
+	lastTxnID    string
+}
+
+// Context returns the <json-value> identifying the monitor
+func (h *MonitorHandle) Context() interface{} {
+	return h.jsonContext
+}
+
+// Requests returns the monitor requests, per table
+func (h *MonitorHandle) Requests() map[string]MonitorRequest {
+	return h.requests
+}
+
+// CondRequests returns the conditional monitor requests, per table,
+// of a monitor created with MonitorCond
+func (h *MonitorHandle) CondRequests() map[string]MonitorCondRequest {
+	return h.condRequests
+}
+
+// LastTxnID returns the id of the last transaction received by a monitor
+// created with MonitorCondSince
+func (h *MonitorHandle) LastTxnID() string {
+	h.ovs.monitorsMutex.Lock()
+	defer h.ovs.monitorsMutex.Unlock()
+	return h.lastTxnID
+}
+
+// Cancel will request cancel the monitor
+// RFC 7047 : monitor_cancel
+func (h *MonitorHandle) Cancel() error {
+	return h.ovs.MonitorCancel(h.jsonContext)
+}
+
+// resume issues the monitor request on the current connection and hands
+// the reply to the handlers
+func (h *MonitorHandle) resume() error {
+	if h.since {
+		_, lastTxnID, reply, err := h.ovs.monitorCondSince(h.database, h.jsonContext, h.LastTxnID(), h.condRequests)
+		if err != nil {
+			return err
+		}
+		h.ovs.setLastTxnID(h.jsonContext, lastTxnID)
+		h.ovs.notifyUpdate2(h.jsonContext, reply)
+		return nil
+	}
+	if h.condRequests != nil {
+		reply, err := h.ovs.monitorCond(h.database, h.jsonContext, h.condRequests)
+		if err != nil {
+			return err
+		}
+		h.ovs.notifyUpdate2(h.jsonContext, reply)
+		return nil
+	}
+	reply, err := h.ovs.monitor(h.database, h.jsonContext, h.requests)
+	if err != nil {
+		return err
+	}
+	h.ovs.notifyUpdate(h.jsonContext, *reply)
+	return nil
+}
+
+func newOvsdbClient(endpoints string, tlsConfig *tls.Config, options *ConnectOptions) *OvsdbClient {
 	ovs := &OvsdbClient{
-		rpcClient:     c,
+		rpcMutex:      &sync.RWMutex{},
 		Schema:        make(map[string]DatabaseSchema),
+		schemaMutex:   &sync.RWMutex{},
 		handlersMutex: &sync.Mutex{},
+		monitorsMutex: &sync.Mutex{},
+		endpoints:     endpoints,
+		tlsConfig:     tlsConfig,
+	}
+	if options != nil {
+		ovs.options = *options
 	}
 	return ovs
 }
@@ -42,6 +130,7 @@ var (
 // Constants defined for libovsdb
 const (
 	defaultTCPAddress  = "127.0.0.1:6640"
+	defaultTCPPort     = "6640"
 	defaultUnixAddress = "/var/run/openvswitch/ovnnb_db.sock"
 	SSL                = "ssl"
 	TCP                = "tcp"
@@ -51,69 +140,353 @@ const (
 // Connect to ovn, using endpoint in format ovsdb Connection Methods
 // If address is empty, use default address for specified protocol
 func Connect(endpoints string, tlsConfig *tls.Config) (*OvsdbClient, error) {
+	return ConnectWithOptions(endpoints, tlsConfig, nil)
+}
+
+// ConnectWithOptions is like Connect but tunes the connection behavior
+// according to the provided ConnectOptions, which may be nil
+func ConnectWithOptions(endpoints string, tlsConfig *tls.Config, options *ConnectOptions) (*OvsdbClient, error) {
+	return connect(context.Background(), endpoints, tlsConfig, options)
+}
+
+// ConnectCtx is like Connect but gives up dialing and fetching the schemas
+// when ctx is done, returning ctx.Err()
+func ConnectCtx(ctx context.Context, endpoints string, tlsConfig *tls.Config) (*OvsdbClient, error) {
+	return connect(ctx, endpoints, tlsConfig, nil)
+}
+
+func connect(ctx context.Context, endpoints string, tlsConfig *tls.Config, options *ConnectOptions) (*OvsdbClient, error) {
+	if options != nil && options.ParallelDial {
+		return connectParallel(ctx, endpoints, tlsConfig, options)
+	}
+	ovs := newOvsdbClient(endpoints, tlsConfig, options)
+	c, err := ovs.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := ovs.attach(ctx, c); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, err
+	}
+	if err := ovs.checkDatabase(); err != nil {
+		ovs.Disconnect()
+		return nil, err
+	}
+	return ovs, nil
+}
+
+// dial connects to the first reachable endpoint
+func (ovs *OvsdbClient) dial(ctx context.Context) (net.Conn, error) {
 	var c net.Conn
+	var scheme, addr string
 	var err error
-	var u *url.URL
 
+	for _, endpoint := range strings.Split(ovs.endpoints, ",") {
+		if scheme, addr, err = parseEndpoint(endpoint); err != nil {
+			return nil, err
+		}
+		if c, err = ovs.dialEndpoint(ctx, scheme, addr); err == nil {
+			return c, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+	}
+
+	return nil, fmt.Errorf("failed to connect to endpoints %q: %v", ovs.endpoints, err)
+}
+
+// dialEndpoint connects to a single parsed endpoint
+func (ovs *OvsdbClient) dialEndpoint(ctx context.Context, scheme, addr string) (net.Conn, error) {
+	ctx, cancel := context.WithTimeout(ctx, ovs.options.dialTimeout())
+	defer cancel()
+	dialer := ovs.options.dialer()
+	if scheme == SSL {
+		return dialTLS(ctx, dialer, addr, ovs.tlsConfig)
+	}
+	return dialer(ctx, scheme, addr)
+}
+
+// connectParallel dials every endpoint at once and keeps the first connection
+// that succeeds and serves options.Database, closing the others
+func connectParallel(ctx context.Context, endpoints string, tlsConfig *tls.Config, options *ConnectOptions) (*OvsdbClient, error) {
+	type target struct {
+		scheme, addr string
+	}
+	type result struct {
+		ovs *OvsdbClient
+		err error
+	}
+
+	var targets []target
 	for _, endpoint := range strings.Split(endpoints, ",") {
-		if u, err = url.Parse(endpoint); err != nil {
+		scheme, addr, err := parseEndpoint(endpoint)
+		if err != nil {
 			return nil, err
 		}
+		targets = append(targets, target{scheme, addr})
+	}
+
+	dialCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	results := make(chan result, len(targets))
+	for _, t := range targets {
+		go func(t target) {
+			ovs := newOvsdbClient(endpoints, tlsConfig, options)
+			c, err := ovs.dialEndpoint(dialCtx, t.scheme, t.addr)
+			if err == nil {
+				if err = ovs.attach(dialCtx, c); err == nil {
+					err = ovs.checkDatabase()
+				}
+				if err != nil {
+					ovs.Disconnect()
+				}
+			}
+			results <- result{ovs, err}
+		}(t)
+	}
+
+	var winner *OvsdbClient
+	var err error
+	for range targets {
+		r := <-results
+		switch {
+		case r.err != nil:
+			err = r.err
+		case winner == nil:
+			winner = r.ovs
+			cancel()
+		default:
+			r.ovs.Disconnect()
+		}
+	}
+	if winner != nil {
+		return winner, nil
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	return nil, fmt.Errorf("failed to connect to endpoints %q: %v", endpoints, err)
+}
+
+// checkDatabase fails if options.Database is set and not served by the server
+func (ovs *OvsdbClient) checkDatabase() error {
+	if ovs.options.Database == "" {
+		return nil
+	}
+	if _, ok := ovs.schema(ovs.options.Database); !ok {
+		return fmt.Errorf("database %s not found", ovs.options.Database)
+	}
+	return nil
+}
+
+// parseEndpoint splits an endpoint in ovsdb Connection Methods format into its
+// protocol and address, filling in the defaults for missing parts
+func parseEndpoint(endpoint string) (string, string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", "", err
+	}
+	switch u.Scheme {
+	case UNIX:
+		path := u.Path
+		if len(path) == 0 {
+			path = defaultUnixAddress
+		}
+		return u.Scheme, path, nil
+	case TCP, SSL:
 		// u.Opaque contains the original endPoint with the leading protocol stripped
 		// off. For example: endPoint is "tcp:127.0.0.1:6640" and u.Opaque is "127.0.0.1:6640"
 		host := u.Opaque
 		if len(host) == 0 {
-			host = defaultTCPAddress
+			return u.Scheme, defaultTCPAddress, nil
 		}
-		switch u.Scheme {
-		case UNIX:
-			path := u.Path
-			if len(path) == 0 {
-				path = defaultUnixAddress
-			}
-			c, err = net.Dial(u.Scheme, path)
-		case TCP:
-			c, err = net.Dial(u.Scheme, host)
-		case SSL:
-			c, err = tls.Dial("tcp", host, tlsConfig)
-		default:
-			err = fmt.Errorf("unknown network protocol %s", u.Scheme)
+		if _, _, err := net.SplitHostPort(host); err == nil {
+			return u.Scheme, host, nil
+		}
+		// No port, IPv6 addresses must then be enclosed in brackets
+		if strings.HasPrefix(host, "[") && strings.HasSuffix(host, "]") {
+			host = host[1 : len(host)-1]
+		} else if strings.Contains(host, ":") {
+			return "", "", fmt.Errorf("invalid address %q in endpoint %q", host, endpoint)
 		}
+		return u.Scheme, net.JoinHostPort(host, defaultTCPPort), nil
+	default:
+		return "", "", fmt.Errorf("unknown network protocol %s", u.Scheme)
+	}
+}
 
-		if err == nil {
-			return newRPC2Client(c)
+// dialTLS is like tls.DialWithDialer but aborts the handshake when ctx is done
+func dialTLS(ctx context.Context, dialer DialFunc, addr string, config *tls.Config) (net.Conn, error) {
+	raw, err := dialer(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	if config == nil {
+		config = &tls.Config{}
+	}
+	if config.ServerName == "" {
+		// Same default as tls.Dial
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			raw.Close()
+			return nil, err
 		}
+		config = config.Clone()
+		config.ServerName = host
+	}
+	conn := tls.Client(raw, config)
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- conn.Handshake()
+	}()
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+		err = ctx.Err()
 	}
+	if err != nil {
+		raw.Close()
+		return nil, err
+	}
+	return conn, nil
+}
 
-	return nil, fmt.Errorf("failed to connect to endpoints %q: %v", endpoints, err)
+// clientCodec wraps the JSON-RPC codec to record why the rpc2 read loop
+// terminated, as rpc2.Client.Run does not report it
+type clientCodec struct {
+	rpc2.Codec
+	mutex   sync.Mutex
+	readErr error
+	closed  bool
+	// lastRead is when the last message was read and busy whether it is
+	// still being handled, which holds up reading the next one
+	lastRead time.Time
+	busy     bool
+}
+
+func newClientCodec(conn net.Conn) *clientCodec {
+	return &clientCodec{Codec: jsonrpc.NewJSONCodec(conn), lastRead: time.Now()}
+}
+
+func (c *clientCodec) ReadHeader(req *rpc2.Request, resp *rpc2.Response) error {
+	c.setBusy(false)
+	err := c.Codec.ReadHeader(req, resp)
+	c.setBusy(true)
+	return c.record(err)
+}
+
+func (c *clientCodec) setBusy(busy bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.busy = busy
+	c.lastRead = time.Now()
+}
+
+// idle returns for how long no message has been read, which is zero while
+// one is being handled
+func (c *clientCodec) idle() time.Duration {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.busy {
+		return 0
+	}
+	return time.Since(c.lastRead)
+}
+
+func (c *clientCodec) ReadRequestBody(x interface{}) error {
+	return c.record(c.Codec.ReadRequestBody(x))
+}
+
+func (c *clientCodec) Close() error {
+	c.mutex.Lock()
+	c.closed = true
+	c.mutex.Unlock()
+	return c.Codec.Close()
+}
+
+func (c *clientCodec) record(err error) error {
+	if err != nil {
+		c.mutex.Lock()
+		if c.readErr == nil {
+			c.readErr = err
+		}
+		c.mutex.Unlock()
+	}
+	return err
+}
+
+// abort closes the connection as a failure caused by err
+func (c *clientCodec) abort(err error) {
+	c.record(err)
+	c.Codec.Close()
+}
+
+// disconnectReason returns nil if the connection was closed locally,
+// or the error that terminated the read loop otherwise
+func (c *clientCodec) disconnectReason() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.closed {
+		return nil
+	}
+	return c.readErr
 }
 
 func newRPC2Client(conn net.Conn) (*OvsdbClient, error) {
-	c := rpc2.NewClientWithCodec(jsonrpc.NewJSONCodec(conn))
+	ovs := newOvsdbClient("", nil, nil)
+	if err := ovs.attach(context.Background(), conn); err != nil {
+		return nil, err
+	}
+	return ovs, nil
+}
+
+// attach runs a new rpc2 client over conn, fetches the database schemas
+// and registers the connection so its notifications reach ovs handlers
+func (ovs *OvsdbClient) attach(ctx context.Context, conn net.Conn) error {
+	codec := newClientCodec(conn)
+	c := rpc2.NewClientWithCodec(codec)
 	c.SetBlocking(true)
 	c.Handle("echo", echo)
 	c.Handle("update", update)
+	c.Handle("update2", update2)
+	c.Handle("update3", update3)
+	c.Handle("locked", locked)
+	c.Handle("stolen", stolen)
 	go c.Run()
 	go handleDisconnectNotification(c)
+	go ovs.keepalive(c, codec)
 
-	ovs := newOvsdbClient(c)
+	prevClient, prevCodec := ovs.setClient(c, codec)
+	// A failed attempt must not hide why the last live connection was lost
+	fail := func(err error) error {
+		c.Close()
+		if prevCodec != nil {
+			ovs.setClient(prevClient, prevCodec)
+		}
+		return err
+	}
 
 	// Process Async Notifications
-	dbs, err := ovs.ListDbs()
+	dbs, err := ovs.listDbs(ctx)
 	if err != nil {
-		c.Close()
-		return nil, err
+		return fail(err)
 	}
 
+	// Readers may be using the current schemas, so swap in a complete set
+	schemas := make(map[string]DatabaseSchema, len(dbs))
 	for _, db := range dbs {
-		schema, err := ovs.GetSchema(db)
-		if err == nil {
-			ovs.Schema[db] = *schema
-		} else {
-			c.Close()
-			return nil, err
+		schema, err := ovs.getSchema(ctx, db)
+		if err != nil {
+			return fail(err)
 		}
+		schemas[db] = *schema
 	}
+	ovs.schemaMutex.Lock()
+	ovs.Schema = schemas
+	ovs.schemaMutex.Unlock()
 
 	connectionsMutex.Lock()
 	defer connectionsMutex.Unlock()
@@ -121,7 +494,159 @@ func newRPC2Client(conn net.Conn) (*OvsdbClient, error) {
 		connections = make(map[*rpc2.Client]*OvsdbClient)
 	}
 	connections[c] = ovs
-	return ovs, nil
+	return nil
+}
+
+// ErrEchoTimeout is the DisconnectReason of a connection dropped because the
+// server did not answer an echo request in time
+var ErrEchoTimeout = errors.New("echo request timed out")
+
+// keepalive sends an echo request on the connection of c once no message has
+// been read for EchoInterval, dropping it if nothing is read within EchoTimeout.
+// A message being handled holds up reading the reply, so it keeps it alive
+func (ovs *OvsdbClient) keepalive(c *rpc2.Client, codec *clientCodec) {
+	interval := ovs.options.echoInterval()
+	if interval < 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.DisconnectNotify():
+			return
+		case <-ticker.C:
+		}
+		if codec.idle() < interval {
+			continue
+		}
+		// Any reply, even an error, shows the server is alive
+		var reply []interface{}
+		timeout := ovs.options.echoTimeout()
+		call := c.Go("echo", []interface{}{}, &reply, make(chan *rpc2.Call, 1))
+		select {
+		case <-call.Done:
+		case <-c.DisconnectNotify():
+			return
+		case <-time.After(timeout):
+			if codec.idle() >= timeout {
+				codec.abort(ErrEchoTimeout)
+				return
+			}
+		}
+	}
+}
+
+// getConnection returns the OvsdbClient using an rpc2 client, if any
+func getConnection(c *rpc2.Client) *OvsdbClient {
+	connectionsMutex.RLock()
+	defer connectionsMutex.RUnlock()
+	return connections[c]
+}
+
+// detach closes the connection of the rpc2 client without notifying handlers
+func detach(c *rpc2.Client) {
+	connectionsMutex.Lock()
+	delete(connections, c)
+	connectionsMutex.Unlock()
+	c.Close()
+}
+
+// setClient makes c, using codec, the current connection and returns the
+// previous one
+func (ovs *OvsdbClient) setClient(c *rpc2.Client, codec *clientCodec) (*rpc2.Client, *clientCodec) {
+	ovs.rpcMutex.Lock()
+	defer ovs.rpcMutex.Unlock()
+	prevClient, prevCodec := ovs.rpcClient, ovs.codec
+	ovs.rpcClient, ovs.codec = c, codec
+	return prevClient, prevCodec
+}
+
+func (ovs *OvsdbClient) client() *rpc2.Client {
+	ovs.rpcMutex.RLock()
+	defer ovs.rpcMutex.RUnlock()
+	return ovs.rpcClient
+}
+
+// Call invokes an arbitrary JSON-RPC method on the server, e.g. one this
+// library does not wrap. It bypasses the schema validation done by the
+// other methods and its effects, such as new monitors, are not tracked
+func (ovs *OvsdbClient) Call(method string, args interface{}, reply interface{}) error {
+	return ovs.client().Call(method, args, reply)
+}
+
+// call invokes method on the server and waits for its reply or for ctx to be
+// done, whichever happens first. rpc2 has no way to withdraw a pending call,
+// so the reply to an abandoned call is discarded when it arrives
+func (ovs *OvsdbClient) call(ctx context.Context, method string, args interface{}, reply interface{}) error {
+	call := ovs.client().Go(method, args, reply, make(chan *rpc2.Call, 1))
+	select {
+	case <-call.Done:
+		return call.Error
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (ovs *OvsdbClient) isClosed() bool {
+	ovs.rpcMutex.RLock()
+	defer ovs.rpcMutex.RUnlock()
+	return ovs.closed
+}
+
+// reconnect redials the endpoints with exponential backoff until it succeeds
+// or MaxRetries attempts have failed. Once connected, the schemas are fetched
+// again, the active monitors are re-issued and ReconnectHandlers are notified
+func (ovs *OvsdbClient) reconnect() {
+	backoff := ovs.options.initialBackoff()
+	for retry := 0; ovs.options.MaxRetries == 0 || retry < ovs.options.MaxRetries; retry++ {
+		time.Sleep(backoff)
+		if ovs.isClosed() {
+			return
+		}
+		if err := ovs.redial(); err == nil {
+			if ovs.isClosed() {
+				ovs.Disconnect()
+				return
+			}
+			for _, handler := range ovs.handlersSnapshot() {
+				if h, ok := handler.(ReconnectHandler); ok {
+					h.Reconnected(ovs)
+				}
+			}
+			return
+		}
+		backoff *= 2
+		if limit := ovs.options.maxBackoff(); backoff > limit {
+			backoff = limit
+		}
+	}
+}
+
+func (ovs *OvsdbClient) redial() error {
+	ctx := context.Background()
+	conn, err := ovs.dial(ctx)
+	if err != nil {
+		return err
+	}
+	ovs.rpcMutex.RLock()
+	prevClient, prevCodec := ovs.rpcClient, ovs.codec
+	ovs.rpcMutex.RUnlock()
+	if err = ovs.attach(ctx, conn); err != nil {
+		return err
+	}
+
+	ovs.monitorsMutex.Lock()
+	monitors := append([]*MonitorHandle(nil), ovs.monitors...)
+	ovs.monitorsMutex.Unlock()
+	for _, m := range monitors {
+		if err := m.resume(); err != nil {
+			detach(ovs.client())
+			ovs.setClient(prevClient, prevCodec)
+			return err
+		}
+	}
+	return nil
 }
 
 // Register registers the supplied NotificationHandler to recieve OVSDB Notifications
@@ -131,6 +656,14 @@ func (ovs *OvsdbClient) Register(handler NotificationHandler) {
 	ovs.handlers = append(ovs.handlers, handler)
 }
 
+// handlersSnapshot returns a copy of the registered handlers, so they are
+// called without holding handlersMutex and may (un)register handlers
+func (ovs *OvsdbClient) handlersSnapshot() []NotificationHandler {
+	ovs.handlersMutex.Lock()
+	defer ovs.handlersMutex.Unlock()
+	return append([]NotificationHandler(nil), ovs.handlers...)
+}
+
 //Get Handler by index
 func getHandlerIndex(handler NotificationHandler, handlers []NotificationHandler) (int, error) {
 	for i, h := range handlers {
@@ -170,21 +703,56 @@ type NotificationHandler interface {
 	Disconnected(*OvsdbClient)
 }
 
+// ReconnectHandler may be implemented by a NotificationHandler to be notified
+// when a connection lost with ConnectOptions.Reconnect set is re-established.
+// By then, the active monitors have been re-issued and their initial contents
+// delivered through Update
+type ReconnectHandler interface {
+	Reconnected(*OvsdbClient)
+}
+
+// Update2Handler may be implemented by a NotificationHandler to receive the
+// updates of MonitorCond and MonitorCondSince monitors as the server sends
+// them, instead of through Update. Update is only given their initial,
+// inserted and deleted rows, since a modified row comes as a row-diff, see
+// RowUpdate2, which a RowUpdate cannot represent
+type Update2Handler interface {
+	Update2(context interface{}, tableUpdates TableUpdates2)
+}
+
 // RFC 7047 : Section 4.1.6 : Echo
 func echo(client *rpc2.Client, args []interface{}, reply *[]interface{}) error {
 	*reply = args
-	connectionsMutex.RLock()
-	defer connectionsMutex.RUnlock()
-	if _, ok := connections[client]; ok {
-		connections[client].handlersMutex.Lock()
-		defer connections[client].handlersMutex.Unlock()
-		for _, handler := range connections[client].handlers {
+	if ovs := getConnection(client); ovs != nil {
+		for _, handler := range ovs.handlersSnapshot() {
 			handler.Echo(nil)
 		}
 	}
 	return nil
 }
 
+// RFC 7047 : Section 4.1.9 : Locked Notification
+// Processing "params": [<id>]
+func locked(client *rpc2.Client, params []interface{}, _ *interface{}) error {
+	if ovs := getConnection(client); ovs != nil {
+		for _, handler := range ovs.handlersSnapshot() {
+			handler.Locked(params)
+		}
+	}
+	return nil
+}
+
+// RFC 7047 : Section 4.1.10 : Stolen Notification
+// Processing "params": [<id>]
+func stolen(client *rpc2.Client, params []interface{}, _ *interface{}) error {
+	if ovs := getConnection(client); ovs != nil {
+		for _, handler := range ovs.handlersSnapshot() {
+			handler.Stolen(params)
+		}
+	}
+	return nil
+}
+
 // RFC 7047 : Update Notification Section 4.1.6
 // Processing "params": [<json-value>, <table-updates>]
 func update(client *rpc2.Client, params []interface{}, _ *interface{}) error {
@@ -210,37 +778,159 @@ func update(client *rpc2.Client, params []interface{}, _ *interface{}) error {
 
 	// Update the local DB cache with the tableUpdates
 	tableUpdates := getTableUpdatesFromRawUnmarshal(rowUpdates)
-	connectionsMutex.RLock()
-	defer connectionsMutex.RUnlock()
-	if _, ok := connections[client]; ok {
-		connections[client].handlersMutex.Lock()
-		defer connections[client].handlersMutex.Unlock()
-		for _, handler := range connections[client].handlers {
-			handler.Update(params[0], tableUpdates)
-		}
+	dispatchUpdate(client, params[0], tableUpdates)
+	return nil
+}
+
+// Update2 Notification of the monitor_cond extension, see ovsdb-server(7)
+// Processing "params": [<json-value>, <table-updates2>]
+func update2(client *rpc2.Client, params []interface{}, _ *interface{}) error {
+	if len(params) < 2 {
+		return errors.New("Invalid Update2 message")
+	}
+
+	raw, ok := params[1].(map[string]interface{})
+	if !ok {
+		return errors.New("Invalid Update2 message")
+	}
+	var rowUpdates TableUpdates2
+
+	b, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	err = json.Unmarshal(b, &rowUpdates)
+	if err != nil {
+		return err
 	}
 
+	if ovs := getConnection(client); ovs != nil {
+		ovs.notifyUpdate2(params[0], rowUpdates)
+	}
 	return nil
 }
 
-// GetSchema returns the schema in use for the provided database name
+// Update3 Notification of the monitor_cond_since extension, see ovsdb-server(7)
+// Processing "params": [<json-value>, <last-txn-id>, <table-updates2>]
+func update3(client *rpc2.Client, params []interface{}, _ *interface{}) error {
+	if len(params) < 3 {
+		return errors.New("Invalid Update3 message")
+	}
+
+	lastTxnID, ok := params[1].(string)
+	if !ok {
+		return errors.New("Invalid Update3 message")
+	}
+	raw, ok := params[2].(map[string]interface{})
+	if !ok {
+		return errors.New("Invalid Update3 message")
+	}
+	var rowUpdates TableUpdates2
+
+	b, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	err = json.Unmarshal(b, &rowUpdates)
+	if err != nil {
+		return err
+	}
+
+	if ovs := getConnection(client); ovs != nil {
+		ovs.setLastTxnID(params[0], lastTxnID)
+		ovs.notifyUpdate2(params[0], rowUpdates)
+	}
+	return nil
+}
+
+// dispatchUpdate hands the tableUpdates to the handlers of the client connection
+func dispatchUpdate(client *rpc2.Client, context interface{}, tableUpdates TableUpdates) {
+	if ovs := getConnection(client); ovs != nil {
+		ovs.notifyUpdate(context, tableUpdates)
+	}
+}
+
+// notifyUpdate hands the tableUpdates to the handlers
+func (ovs *OvsdbClient) notifyUpdate(context interface{}, tableUpdates TableUpdates) {
+	for _, handler := range ovs.handlersSnapshot() {
+		handler.Update(context, tableUpdates)
+	}
+}
+
+// notifyUpdate2 hands the tableUpdates to the Update2Handlers, and what
+// a TableUpdates can hold of them to the other handlers
+func (ovs *OvsdbClient) notifyUpdate2(context interface{}, tableUpdates TableUpdates2) {
+	var translated *TableUpdates
+	for _, handler := range ovs.handlersSnapshot() {
+		if h, ok := handler.(Update2Handler); ok {
+			h.Update2(context, tableUpdates)
+			continue
+		}
+		if translated == nil {
+			updates := getTableUpdatesFromRawUnmarshal2(tableUpdates)
+			translated = &updates
+		}
+		handler.Update(context, *translated)
+	}
+}
+
+// GetSchema returns the schema in use for the provided database name, as sent
+// by the server. Tables without columns are kept and listed by SkippedTables.
+// Schema is replaced with a copy holding it rather than modified in place
 // RFC 7047 : get_schema
-func (ovs OvsdbClient) GetSchema(dbName string) (*DatabaseSchema, error) {
+func (ovs *OvsdbClient) GetSchema(dbName string) (*DatabaseSchema, error) {
+	schema, err := ovs.getSchema(context.Background(), dbName)
+	if err != nil {
+		return nil, err
+	}
+	ovs.schemaMutex.Lock()
+	defer ovs.schemaMutex.Unlock()
+	schemas := make(map[string]DatabaseSchema, len(ovs.Schema)+1)
+	for db, s := range ovs.Schema {
+		schemas[db] = s
+	}
+	schemas[dbName] = *schema
+	ovs.Schema = schemas
+	return schema, nil
+}
+
+// Schemas returns the schemas fetched from the server, per database name.
+// Unlike reading Schema directly, it is safe while the client reconnects.
+// The returned map must not be modified
+func (ovs *OvsdbClient) Schemas() map[string]DatabaseSchema {
+	ovs.schemaMutex.RLock()
+	defer ovs.schemaMutex.RUnlock()
+	return ovs.Schema
+}
+
+// schema returns the schema of database, if it has been fetched
+func (ovs *OvsdbClient) schema(database string) (DatabaseSchema, bool) {
+	ovs.schemaMutex.RLock()
+	defer ovs.schemaMutex.RUnlock()
+	schema, ok := ovs.Schema[database]
+	return schema, ok
+}
+
+func (ovs *OvsdbClient) getSchema(ctx context.Context, dbName string) (*DatabaseSchema, error) {
 	args := NewGetSchemaArgs(dbName)
 	var reply DatabaseSchema
-	err := ovs.rpcClient.Call("get_schema", args, &reply)
+	err := ovs.call(ctx, "get_schema", args, &reply)
 	if err != nil {
 		return nil, err
 	}
-	ovs.Schema[dbName] = reply
-	return &reply, err
+	reply.recordTablesWithoutColumns()
+	return &reply, nil
 }
 
 // ListDbs returns the list of databases on the server
 // RFC 7047 : list_dbs
-func (ovs OvsdbClient) ListDbs() ([]string, error) {
+func (ovs *OvsdbClient) ListDbs() ([]string, error) {
+	return ovs.listDbs(context.Background())
+}
+
+func (ovs *OvsdbClient) listDbs(ctx context.Context) ([]string, error) {
 	var dbs []string
-	err := ovs.rpcClient.Call("list_dbs", nil, &dbs)
+	err := ovs.call(ctx, "list_dbs", nil, &dbs)
 	if err != nil {
 		return nil, fmt.Errorf("ListDbs failure - %v", err)
 	}
@@ -249,77 +939,216 @@ func (ovs OvsdbClient) ListDbs() ([]string, error) {
 
 // Transact performs the provided Operation's on the database
 // RFC 7047 : transact
-func (ovs OvsdbClient) Transact(database string, operation ...Operation) ([]OperationResult, error) {
+func (ovs *OvsdbClient) Transact(database string, operation ...Operation) ([]OperationResult, error) {
+	return ovs.TransactCtx(context.Background(), database, operation...)
+}
+
+// OperationError is the error reported for one result of a transaction
+type OperationError struct {
+	// Index of the result, it is past the last operation for commit errors
+	Index   int
+	Error   string
+	Details string
+}
+
+// TransactionError is returned by TransactAndCheck when any result of the
+// transaction reports an error
+type TransactionError struct {
+	Errors []OperationError
+}
+
+func (e *TransactionError) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, opErr := range e.Errors {
+		msg := fmt.Sprintf("operation %d: %s", opErr.Index, opErr.Error)
+		if opErr.Details != "" {
+			msg += ": " + opErr.Details
+		}
+		msgs = append(msgs, msg)
+	}
+	return "transaction failed: " + strings.Join(msgs, "; ")
+}
+
+// TransactAndCheck is like Transact but also returns a *TransactionError if
+// any result reports an error, along with the results
+func (ovs *OvsdbClient) TransactAndCheck(database string, operation ...Operation) ([]OperationResult, error) {
+	reply, err := ovs.Transact(database, operation...)
+	if err != nil {
+		return nil, err
+	}
+	var txnErr TransactionError
+	for i, result := range reply {
+		if result.Error != "" {
+			txnErr.Errors = append(txnErr.Errors, OperationError{Index: i, Error: result.Error, Details: result.Details})
+		}
+	}
+	if len(txnErr.Errors) > 0 {
+		return reply, &txnErr
+	}
+	return reply, nil
+}
+
+// TransactCtx is like Transact but stops waiting for the reply when ctx is done,
+// returning ctx.Err(). The server may still commit an abandoned transaction
+func (ovs *OvsdbClient) TransactCtx(ctx context.Context, database string, operation ...Operation) ([]OperationResult, error) {
 	var reply []OperationResult
-	db, ok := ovs.Schema[database]
+	db, ok := ovs.schema(database)
 	if !ok {
 		return nil, fmt.Errorf("invalid Database %q Schema", database)
 	}
 
-	if ok := db.validateOperations(operation...); !ok {
-		return nil, errors.New("Validation failed for the operation")
+	if err := db.validateOperations(operation...); err != nil {
+		return nil, err
 	}
 
 	args := NewTransactArgs(database, operation...)
-	err := ovs.rpcClient.Call("transact", args, &reply)
+	err := ovs.call(ctx, "transact", args, &reply)
 	if err != nil {
 		return nil, err
 	}
 	return reply, nil
 }
 
-// MonitorAll is a convenience method to monitor every table/column
-func (ovs OvsdbClient) MonitorAll(database string, jsonContext interface{}) (*TableUpdates, error) {
-	schema, ok := ovs.Schema[database]
+// lockResult is the result of a lock or steal request
+type lockResult struct {
+	Locked bool `json:"locked"`
+}
+
+// Lock requests the lock with the provided id and returns whether it was
+// granted immediately. Otherwise, the lock is acquired once the server sends
+// the Locked notification
+// RFC 7047 : lock
+func (ovs *OvsdbClient) Lock(id string) (bool, error) {
+	var reply lockResult
+	args := NewLockArgs(id)
+	err := ovs.client().Call("lock", args, &reply)
+	if err != nil {
+		return false, err
+	}
+	return reply.Locked, nil
+}
+
+// Unlock releases the lock with the provided id, or cancels a pending request
+// RFC 7047 : unlock
+func (ovs *OvsdbClient) Unlock(id string) error {
+	var reply struct{}
+	args := NewLockArgs(id)
+	return ovs.client().Call("unlock", args, &reply)
+}
+
+// Steal acquires the lock with the provided id, taking it from its current
+// owner which receives a Stolen notification
+// RFC 7047 : steal
+func (ovs *OvsdbClient) Steal(id string) error {
+	var reply lockResult
+	args := NewLockArgs(id)
+	return ovs.client().Call("steal", args, &reply)
+}
+
+// MonitorAll is a convenience method to monitor every table/column. Tables
+// without columns are left out, see SkippedTables
+func (ovs *OvsdbClient) MonitorAll(database string, jsonContext interface{}) (*MonitorHandle, *TableUpdates, error) {
+	return ovs.MonitorAllExcept(database, jsonContext, nil)
+}
+
+// MonitorAllExcept is like MonitorAll but leaves out the columns listed in
+// exclude for each table, or the whole table if its list is empty
+func (ovs *OvsdbClient) MonitorAllExcept(database string, jsonContext interface{}, exclude map[string][]string) (*MonitorHandle, *TableUpdates, error) {
+	schema, ok := ovs.schema(database)
 	if !ok {
-		return nil, fmt.Errorf("invalid Database %q Schema", database)
+		return nil, nil, fmt.Errorf("invalid Database %q Schema", database)
 	}
 
+	skipped := make(map[string]bool, len(schema.SkippedTables()))
+	for _, table := range schema.SkippedTables() {
+		skipped[table] = true
+	}
 	requests := make(map[string]MonitorRequest)
 	for table, tableSchema := range schema.Tables {
+		excluded, ok := exclude[table]
+		if skipped[table] || (ok && len(excluded) == 0) {
+			continue
+		}
+		skip := make(map[string]bool, len(excluded))
+		for _, column := range excluded {
+			skip[column] = true
+		}
 		var columns []string
 		for column := range tableSchema.Columns {
-			columns = append(columns, column)
+			if !skip[column] {
+				columns = append(columns, column)
+			}
+		}
+		if len(columns) == 0 {
+			continue
 		}
 		requests[table] = MonitorRequest{
 			Columns: columns,
-			Select: MonitorSelect{
-				Initial: true,
-				Insert:  true,
-				Delete:  true,
-				Modify:  true,
-			}}
+			Select:  MonitorAllSelect(),
+		}
 	}
 	return ovs.Monitor(database, jsonContext, requests)
 }
 
 // MonitorCancel will request cancel a previously issued monitor request
 // RFC 7047 : monitor_cancel
-func (ovs OvsdbClient) MonitorCancel(jsonContext interface{}) error {
+func (ovs *OvsdbClient) MonitorCancel(jsonContext interface{}) error {
 	var reply OperationResult
 
 	args := NewMonitorCancelArgs(jsonContext)
 
-	err := ovs.rpcClient.Call("monitor_cancel", args, &reply)
+	err := ovs.client().Call("monitor_cancel", args, &reply)
 	if err != nil {
 		return err
 	}
 	if reply.Error != "" {
 		return fmt.Errorf("Error while executing transaction: %s", reply.Error)
 	}
+
+	ovs.monitorsMutex.Lock()
+	defer ovs.monitorsMutex.Unlock()
+	for i, m := range ovs.monitors {
+		if reflect.DeepEqual(m.jsonContext, jsonContext) {
+			ovs.monitors = append(ovs.monitors[:i], ovs.monitors[i+1:]...)
+			break
+		}
+	}
 	return nil
 }
 
-// Monitor will provide updates for a given table/column
+// Monitor will provide updates for a given table/column, along with a handle
+// to cancel them
 // RFC 7047 : monitor
-func (ovs OvsdbClient) Monitor(database string, jsonContext interface{}, requests map[string]MonitorRequest) (*TableUpdates, error) {
+func (ovs *OvsdbClient) Monitor(database string, jsonContext interface{}, requests map[string]MonitorRequest) (*MonitorHandle, *TableUpdates, error) {
+	if err := ovs.validateMonitorRequests(database, requests); err != nil {
+		return nil, nil, err
+	}
+	reply, err := ovs.monitor(database, jsonContext, requests)
+	if err != nil {
+		return nil, nil, err
+	}
+	handle := &MonitorHandle{ovs: ovs, database: database, jsonContext: jsonContext, requests: requests}
+	ovs.monitorsMutex.Lock()
+	defer ovs.monitorsMutex.Unlock()
+	ovs.monitors = append(ovs.monitors, handle)
+	return handle, reply, nil
+}
+
+// MonitorRaw is like Monitor for callers managing the <json-value> themselves,
+// the monitor is cancelled by passing the same value to MonitorCancel
+func (ovs *OvsdbClient) MonitorRaw(database string, jsonContext interface{}, requests map[string]MonitorRequest) (*TableUpdates, error) {
+	_, reply, err := ovs.Monitor(database, jsonContext, requests)
+	return reply, err
+}
+
+func (ovs *OvsdbClient) monitor(database string, jsonContext interface{}, requests map[string]MonitorRequest) (*TableUpdates, error) {
 	var reply TableUpdates
 
 	args := NewMonitorArgs(database, jsonContext, requests)
 
 	// This totally sucks. Refer to golang JSON issue #6213
 	var response map[string]map[string]RowUpdate
-	err := ovs.rpcClient.Call("monitor", args, &response)
+	err := ovs.client().Call("monitor", args, &response)
 	reply = getTableUpdatesFromRawUnmarshal(response)
 	if err != nil {
 		return nil, err
@@ -327,6 +1156,129 @@ func (ovs OvsdbClient) Monitor(database string, jsonContext interface{}, request
 	return &reply, err
 }
 
+// MonitorCond will provide updates for the rows of a given table/column matching
+// the conditions of the requests, along with a handle to cancel them.
+// Updates are then received as update2 notifications, see Update2Handler
+// ovsdb-server(7) : monitor_cond
+func (ovs *OvsdbClient) MonitorCond(database string, jsonContext interface{}, requests map[string]MonitorCondRequest) (*MonitorHandle, TableUpdates2, error) {
+	if err := ovs.validateMonitorCondRequests(database, requests); err != nil {
+		return nil, nil, err
+	}
+	reply, err := ovs.monitorCond(database, jsonContext, requests)
+	if err != nil {
+		return nil, nil, err
+	}
+	handle := &MonitorHandle{ovs: ovs, database: database, jsonContext: jsonContext, condRequests: requests}
+	ovs.monitorsMutex.Lock()
+	defer ovs.monitorsMutex.Unlock()
+	ovs.monitors = append(ovs.monitors, handle)
+	return handle, reply, nil
+}
+
+func (ovs *OvsdbClient) monitorCond(database string, jsonContext interface{}, requests map[string]MonitorCondRequest) (TableUpdates2, error) {
+	args := NewMonitorCondArgs(database, jsonContext, requests)
+
+	var reply TableUpdates2
+	err := ovs.client().Call("monitor_cond", args, &reply)
+	if err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
+// MonitorCondSince is like MonitorCond but resumes the monitor from the
+// transaction with id lastTxnID, or from scratch if it is empty. found reports
+// whether the server knew lastTxnID, in which case updates only hold the changes
+// since that transaction. Further updates are received as update3 notifications.
+// The handle tracks the id of the last transaction received, which is used to
+// resume the monitor on reconnection
+// ovsdb-server(7) : monitor_cond_since
+func (ovs *OvsdbClient) MonitorCondSince(database string, jsonContext interface{}, lastTxnID string, requests map[string]MonitorCondRequest) (handle *MonitorHandle, found bool, updates TableUpdates2, err error) {
+	if err = ovs.validateMonitorCondRequests(database, requests); err != nil {
+		return nil, false, nil, err
+	}
+	found, lastTxnID, updates, err = ovs.monitorCondSince(database, jsonContext, lastTxnID, requests)
+	if err != nil {
+		return nil, false, nil, err
+	}
+	handle = &MonitorHandle{ovs: ovs, database: database, jsonContext: jsonContext, condRequests: requests, since: true, lastTxnID: lastTxnID}
+	ovs.monitorsMutex.Lock()
+	defer ovs.monitorsMutex.Unlock()
+	ovs.monitors = append(ovs.monitors, handle)
+	return handle, found, updates, nil
+}
+
+// zeroTxnID requests monitor_cond_since to send all the rows
+const zeroTxnID = "00000000-0000-0000-0000-000000000000"
+
+func (ovs *OvsdbClient) monitorCondSince(database string, jsonContext interface{}, lastTxnID string, requests map[string]MonitorCondRequest) (bool, string, TableUpdates2, error) {
+	if lastTxnID == "" {
+		lastTxnID = zeroTxnID
+	}
+	args := NewMonitorCondSinceArgs(database, jsonContext, requests, lastTxnID)
+
+	// Processing "result": [<found>, <last-txn-id>, <table-updates2>]
+	var response []json.RawMessage
+	err := ovs.client().Call("monitor_cond_since", args, &response)
+	if err != nil {
+		return false, "", nil, err
+	}
+	if len(response) != 3 {
+		return false, "", nil, errors.New("Invalid monitor_cond_since reply")
+	}
+	var found bool
+	var rowUpdates TableUpdates2
+	if err = json.Unmarshal(response[0], &found); err != nil {
+		return false, "", nil, err
+	}
+	if err = json.Unmarshal(response[1], &lastTxnID); err != nil {
+		return false, "", nil, err
+	}
+	if err = json.Unmarshal(response[2], &rowUpdates); err != nil {
+		return false, "", nil, err
+	}
+	return found, lastTxnID, rowUpdates, nil
+}
+
+// setLastTxnID records the last transaction received by the monitor jsonContext
+func (ovs *OvsdbClient) setLastTxnID(jsonContext interface{}, lastTxnID string) {
+	ovs.monitorsMutex.Lock()
+	defer ovs.monitorsMutex.Unlock()
+	for _, m := range ovs.monitors {
+		if m.since && reflect.DeepEqual(m.jsonContext, jsonContext) {
+			m.lastTxnID = lastTxnID
+		}
+	}
+}
+
+// validateMonitorRequests catches requests for tables or columns missing from
+// the schema before the server rejects the whole monitor
+func (ovs *OvsdbClient) validateMonitorRequests(database string, requests map[string]MonitorRequest) error {
+	db, ok := ovs.schema(database)
+	if !ok {
+		return fmt.Errorf("invalid Database %q Schema", database)
+	}
+	return db.validateMonitorRequests(requests)
+}
+
+func (ovs *OvsdbClient) validateMonitorCondRequests(database string, requests map[string]MonitorCondRequest) error {
+	plain := make(map[string]MonitorRequest, len(requests))
+	for table, request := range requests {
+		plain[table] = request.MonitorRequest
+	}
+	if err := ovs.validateMonitorRequests(database, plain); err != nil {
+		return err
+	}
+	for table, request := range requests {
+		for _, condition := range request.Where {
+			if err := validateCondition(table, condition); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 func getTableUpdatesFromRawUnmarshal(raw map[string]map[string]RowUpdate) TableUpdates {
 	var tableUpdates TableUpdates
 	tableUpdates.Updates = make(map[string]TableUpdate)
@@ -337,28 +1289,76 @@ func getTableUpdatesFromRawUnmarshal(raw map[string]map[string]RowUpdate) TableU
 	return tableUpdates
 }
 
-func clearConnection(c *rpc2.Client) {
+// getTableUpdatesFromRawUnmarshal2 translates <table-updates2> into TableUpdates.
+// Initial and inserted rows are set as New and deleted rows get an empty Old.
+// Modified rows only come as row-diffs and are left out
+func getTableUpdatesFromRawUnmarshal2(raw TableUpdates2) TableUpdates {
+	var tableUpdates TableUpdates
+	tableUpdates.Updates = make(map[string]TableUpdate)
+	for table, update := range raw {
+		rows := make(map[string]RowUpdate)
+		for uuid, rowUpdate2 := range update {
+			for kind, row := range rowUpdate2 {
+				switch kind {
+				case "initial", "insert":
+					rows[uuid] = RowUpdate{New: row}
+				case "delete":
+					rows[uuid] = RowUpdate{Old: Row{Fields: make(map[string]interface{})}}
+				}
+			}
+		}
+		tableUpdates.Updates[table] = TableUpdate{rows}
+	}
+	return tableUpdates
+}
+
+func clearConnection(c *rpc2.Client) *OvsdbClient {
 	connectionsMutex.Lock()
-	defer connectionsMutex.Unlock()
-	if _, ok := connections[c]; ok {
-		for _, handler := range connections[c].handlers {
+	ovs, ok := connections[c]
+	delete(connections, c)
+	connectionsMutex.Unlock()
+	if ok {
+		for _, handler := range ovs.handlersSnapshot() {
 			if handler != nil {
-				handler.Disconnected(connections[c])
+				handler.Disconnected(ovs)
 			}
 		}
 	}
-	delete(connections, c)
+	return ovs
 }
 
 func handleDisconnectNotification(c *rpc2.Client) {
 	disconnected := c.DisconnectNotify()
 	select {
 	case <-disconnected:
-		clearConnection(c)
+		ovs := clearConnection(c)
+		if ovs != nil && ovs.options.Reconnect && ovs.DisconnectReason() != nil {
+			ovs.reconnect()
+		}
 	}
 }
 
-// Disconnect will close the OVSDB connection
-func (ovs OvsdbClient) Disconnect() {
-	ovs.rpcClient.Close()
+// Disconnect will close the OVSDB connection. It is safe to call it more than
+// once and from multiple goroutines
+func (ovs *OvsdbClient) Disconnect() {
+	ovs.rpcMutex.Lock()
+	ovs.closed = true
+	c := ovs.rpcClient
+	ovs.rpcMutex.Unlock()
+	if c != nil {
+		c.Close()
+	}
+}
+
+// DisconnectReason returns the error that caused the connection to be lost.
+// It returns nil while connected or if the connection was closed with Disconnect,
+// io.EOF if the server closed the connection and the underlying read or decoding
+// error (e.g. malformed JSON-RPC) otherwise
+func (ovs *OvsdbClient) DisconnectReason() error {
+	ovs.rpcMutex.RLock()
+	defer ovs.rpcMutex.RUnlock()
+	if ovs.codec == nil {
+		return nil
+	}
+	return ovs.codec.disconnectReason()
 }
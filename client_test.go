@@ -0,0 +1,1198 @@
+package libovsdb
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cenkalti/rpc2"
+	"github.com/cenkalti/rpc2/jsonrpc"
+)
+
+var testSchema = DatabaseSchema{
+	Name:    "Open_vSwitch",
+	Version: "8.2.0",
+	Tables: map[string]TableSchema{
+		"Bridge": {
+			Columns: map[string]ColumnSchema{
+				"name":         {Type: "string"},
+				"external_ids": {Type: map[string]interface{}{"key": "string", "value": "string", "min": 0, "max": "unlimited"}},
+			},
+			Indexes: [][]string{{"name"}},
+		},
+		// A partial schema may have tables without columns
+		"Empty": {},
+	},
+}
+
+// testServer is a minimal OVSDB server for testSchema
+type testServer struct {
+	mutex     sync.Mutex
+	client    *rpc2.Client
+	monitors  []interface{}
+	requests  []interface{}
+	cancelled []interface{}
+	locks     map[string]bool
+	txnIDs    []interface{}
+	echoes    int
+	// stall, if set, delays transact replies until it is closed
+	stall chan struct{}
+	// deaf, if set, delays echo replies until it is closed
+	deaf chan struct{}
+}
+
+// serve handles the OVSDB methods on conn
+func (s *testServer) serve(conn net.Conn) *rpc2.Client {
+	server := rpc2.NewClientWithCodec(jsonrpc.NewJSONCodec(conn))
+	server.Handle("list_dbs", func(_ *rpc2.Client, _ []interface{}, reply *[]string) error {
+		*reply = []string{testSchema.Name}
+		return nil
+	})
+	server.Handle("get_schema", func(_ *rpc2.Client, args []interface{}, reply *DatabaseSchema) error {
+		if len(args) != 1 || args[0] != testSchema.Name {
+			return errors.New("unknown database")
+		}
+		*reply = testSchema
+		return nil
+	})
+	server.Handle("echo", func(_ *rpc2.Client, args []interface{}, reply *[]interface{}) error {
+		if s.deaf != nil {
+			<-s.deaf
+		}
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		s.echoes++
+		*reply = args
+		return nil
+	})
+	server.Handle("monitor", func(_ *rpc2.Client, args []interface{}, reply *map[string]interface{}) error {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		s.monitors = append(s.monitors, args[1])
+		s.requests = append(s.requests, args[2])
+		*reply = map[string]interface{}{"Bridge": map[string]interface{}{}}
+		return nil
+	})
+	server.Handle("monitor_cond", func(_ *rpc2.Client, args []interface{}, reply *map[string]interface{}) error {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		s.monitors = append(s.monitors, args[1])
+		*reply = map[string]interface{}{"Bridge": map[string]interface{}{
+			"a": map[string]interface{}{"initial": map[string]interface{}{"name": "br0"}},
+		}}
+		return nil
+	})
+	server.Handle("monitor_cond_since", func(_ *rpc2.Client, args []interface{}, reply *[]interface{}) error {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		s.txnIDs = append(s.txnIDs, args[3])
+		*reply = []interface{}{args[3] != zeroTxnID, "txn1", map[string]interface{}{}}
+		return nil
+	})
+	server.Handle("monitor_cancel", func(_ *rpc2.Client, args []interface{}, reply *OperationResult) error {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		s.cancelled = append(s.cancelled, args[0])
+		return nil
+	})
+	server.Handle("lock", func(_ *rpc2.Client, args []interface{}, reply *map[string]bool) error {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		id := args[0].(string)
+		*reply = map[string]bool{"locked": !s.locks[id]}
+		if s.locks == nil {
+			s.locks = make(map[string]bool)
+		}
+		s.locks[id] = true
+		return nil
+	})
+	server.Handle("steal", func(_ *rpc2.Client, args []interface{}, reply *map[string]bool) error {
+		*reply = map[string]bool{"locked": true}
+		return nil
+	})
+	server.Handle("unlock", func(_ *rpc2.Client, args []interface{}, reply *map[string]bool) error {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		delete(s.locks, args[0].(string))
+		*reply = map[string]bool{}
+		return nil
+	})
+	server.Handle("get_server_id", func(_ *rpc2.Client, args []interface{}, reply *string) error {
+		if len(args) != 1 {
+			return errors.New("expected the database name")
+		}
+		*reply = "server-id-" + args[0].(string)
+		return nil
+	})
+	server.Handle("transact", func(_ *rpc2.Client, args []interface{}, reply *[]OperationResult) error {
+		if s.stall != nil {
+			<-s.stall
+		}
+		*reply = make([]OperationResult, len(args)-1)
+		for i, op := range args[1:] {
+			if op.(map[string]interface{})["op"] == "abort" {
+				(*reply)[i] = OperationResult{Error: "aborted", Details: "aborted by request"}
+				break
+			}
+		}
+		return nil
+	})
+	go server.Run()
+	s.mutex.Lock()
+	s.client = server
+	s.mutex.Unlock()
+	return server
+}
+
+// notify sends a notification on the last served connection
+func (s *testServer) notify(method string, params []interface{}) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.client.Notify(method, params)
+}
+
+func (s *testServer) monitorContexts() []interface{} {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]interface{}(nil), s.monitors...)
+}
+
+// listen serves every connection accepted on a local TCP listener
+// and returns the listener and the accepted connections
+func (s *testServer) listen(t *testing.T) (net.Listener, chan net.Conn) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	conns := make(chan net.Conn, 10)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			s.serve(conn)
+			conns <- conn
+		}
+	}()
+	return ln, conns
+}
+
+// newTestClient returns an OvsdbClient connected to a test server
+func newTestClient(t *testing.T) (*OvsdbClient, *testServer, net.Conn) {
+	clientConn, serverConn := net.Pipe()
+	server := &testServer{}
+	server.serve(serverConn)
+	ovs, err := newRPC2Client(clientConn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ovs, server, serverConn
+}
+
+type disconnectHandler struct {
+	disconnected chan *OvsdbClient
+}
+
+func newDisconnectHandler() *disconnectHandler {
+	return &disconnectHandler{disconnected: make(chan *OvsdbClient, 1)}
+}
+
+func (h *disconnectHandler) Update(context interface{}, tableUpdates TableUpdates) {}
+func (h *disconnectHandler) Locked([]interface{})                                  {}
+func (h *disconnectHandler) Stolen([]interface{})                                  {}
+func (h *disconnectHandler) Echo([]interface{})                                    {}
+func (h *disconnectHandler) Disconnected(ovs *OvsdbClient) {
+	h.disconnected <- ovs
+}
+
+func (h *disconnectHandler) wait(t *testing.T) *OvsdbClient {
+	select {
+	case ovs := <-h.disconnected:
+		return ovs
+	case <-time.After(5 * time.Second):
+		t.Fatal("Disconnected notification not received")
+	}
+	return nil
+}
+
+func TestDisconnectReasonCodecError(t *testing.T) {
+	ovs, _, serverConn := newTestClient(t)
+	handler := newDisconnectHandler()
+	ovs.Register(handler)
+
+	if _, err := serverConn.Write([]byte("}garbage{")); err != nil {
+		t.Fatal(err)
+	}
+	handler.wait(t)
+
+	err := ovs.DisconnectReason()
+	if err == nil || err == io.EOF {
+		t.Errorf("Expected a codec error, got %v", err)
+	}
+}
+
+func TestDisconnectReasonServerClose(t *testing.T) {
+	ovs, _, serverConn := newTestClient(t)
+	handler := newDisconnectHandler()
+	ovs.Register(handler)
+
+	serverConn.Close()
+	handler.wait(t)
+
+	if err := ovs.DisconnectReason(); err != io.EOF {
+		t.Errorf("Expected %v, got %v", io.EOF, err)
+	}
+}
+
+func TestDisconnectReasonNeverConnected(t *testing.T) {
+	ovs := newOvsdbClient("", nil, nil)
+	if err := ovs.DisconnectReason(); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+func TestDisconnectReasonAfterFailedReconnect(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	// Serve the first connection only, the reconnection attempts are dropped
+	server := &testServer{}
+	first := make(chan net.Conn, 1)
+	retries := make(chan struct{}, 10)
+	go func() {
+		for accepted := 0; ; accepted++ {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			if accepted == 0 {
+				server.serve(conn)
+				first <- conn
+				continue
+			}
+			conn.Close()
+			retries <- struct{}{}
+		}
+	}()
+
+	ovs, err := ConnectWithOptions("tcp:"+ln.Addr().String(), nil, &ConnectOptions{
+		Reconnect:      true,
+		MaxRetries:     2,
+		InitialBackoff: 10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ovs.Disconnect()
+
+	(<-first).Close()
+	for i := 0; i < 2; i++ {
+		select {
+		case <-retries:
+		case <-time.After(5 * time.Second):
+			t.Fatal("Reconnection attempt not received")
+		}
+	}
+	time.Sleep(100 * time.Millisecond)
+	if err := ovs.DisconnectReason(); err != io.EOF {
+		t.Errorf("Expected %v, got %v", io.EOF, err)
+	}
+}
+
+func TestDisconnectReasonLocalClose(t *testing.T) {
+	ovs, _, _ := newTestClient(t)
+	handler := newDisconnectHandler()
+	ovs.Register(handler)
+
+	ovs.Disconnect()
+	handler.wait(t)
+
+	if err := ovs.DisconnectReason(); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+type reconnectHandler struct {
+	*disconnectHandler
+	updates     chan interface{}
+	reconnected chan *OvsdbClient
+}
+
+func (h *reconnectHandler) Update(context interface{}, tableUpdates TableUpdates) {
+	h.updates <- context
+}
+
+func (h *reconnectHandler) Reconnected(ovs *OvsdbClient) {
+	h.reconnected <- ovs
+}
+
+func TestReconnect(t *testing.T) {
+	server := &testServer{}
+	ln, conns := server.listen(t)
+	defer ln.Close()
+
+	ovs, err := ConnectWithOptions("tcp:"+ln.Addr().String(), nil, &ConnectOptions{
+		Reconnect:      true,
+		InitialBackoff: 10 * time.Millisecond,
+		MaxBackoff:     50 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ovs.Disconnect()
+	handler := &reconnectHandler{
+		disconnectHandler: newDisconnectHandler(),
+		updates:           make(chan interface{}, 1),
+		reconnected:       make(chan *OvsdbClient, 1),
+	}
+	ovs.Register(handler)
+
+	requests := map[string]MonitorRequest{"Bridge": {Columns: []string{"name"}}}
+	if _, _, err := ovs.Monitor("Open_vSwitch", "ctx", requests); err != nil {
+		t.Fatal(err)
+	}
+
+	// Drop the connection server-side
+	(<-conns).Close()
+	handler.wait(t)
+
+	select {
+	case <-handler.reconnected:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Reconnected notification not received")
+	}
+	if context := <-handler.updates; context != "ctx" {
+		t.Errorf("Expected an update for context ctx, got %v", context)
+	}
+	if contexts := server.monitorContexts(); !reflect.DeepEqual(contexts, []interface{}{"ctx", "ctx"}) {
+		t.Errorf("Expected the monitor to be re-issued, got %v", contexts)
+	}
+	if _, ok := ovs.Schema["Open_vSwitch"]; !ok {
+		t.Error("Expected the schema to be fetched again")
+	}
+}
+
+func TestReconnectWhileTransacting(t *testing.T) {
+	server := &testServer{}
+	ln, conns := server.listen(t)
+	defer ln.Close()
+
+	ovs, err := ConnectWithOptions("tcp:"+ln.Addr().String(), nil, &ConnectOptions{
+		Reconnect:      true,
+		InitialBackoff: 10 * time.Millisecond,
+		MaxBackoff:     50 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ovs.Disconnect()
+	handler := &reconnectHandler{
+		disconnectHandler: newDisconnectHandler(),
+		reconnected:       make(chan *OvsdbClient, 1),
+	}
+	ovs.Register(handler)
+
+	// The schemas are fetched again while transactions look them up
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		operation := Operation{Op: "insert", Table: "Bridge", Row: map[string]interface{}{"name": "br0"}}
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			ovs.Transact("Open_vSwitch", operation)
+		}
+	}()
+
+	(<-conns).Close()
+	handler.wait(t)
+	select {
+	case <-handler.reconnected:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Reconnected notification not received")
+	}
+	close(done)
+	<-stopped
+}
+
+func TestConnectCtxCancel(t *testing.T) {
+	// A listener that never answers list_dbs
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = ConnectCtx(ctx, "tcp:"+ln.Addr().String(), nil)
+	if err != context.DeadlineExceeded {
+		t.Errorf("Expected %v, got %v", context.DeadlineExceeded, err)
+	}
+}
+
+func TestTransactCtx(t *testing.T) {
+	ovs, server, _ := newTestClient(t)
+	defer ovs.Disconnect()
+	operation := Operation{Op: "insert", Table: "Bridge", Row: map[string]interface{}{"name": "br0"}}
+
+	results, err := ovs.TransactCtx(context.Background(), "Open_vSwitch", operation)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 {
+		t.Errorf("Expected 1 result, got %d", len(results))
+	}
+
+	server.stall = make(chan struct{})
+	defer close(server.stall)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err = ovs.TransactCtx(ctx, "Open_vSwitch", operation); err != context.DeadlineExceeded {
+		t.Errorf("Expected %v, got %v", context.DeadlineExceeded, err)
+	}
+}
+
+func TestMonitorHandleCancel(t *testing.T) {
+	ovs, server, _ := newTestClient(t)
+	defer ovs.Disconnect()
+
+	requests := map[string]MonitorRequest{"Bridge": {Columns: []string{"name"}}}
+	handle, updates, err := ovs.Monitor("Open_vSwitch", "ctx", requests)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := updates.Updates["Bridge"]; !ok {
+		t.Errorf("Expected initial Bridge updates, got %v", updates)
+	}
+	if handle.Context() != "ctx" || !reflect.DeepEqual(handle.Requests(), requests) {
+		t.Errorf("Unexpected handle %v", handle)
+	}
+
+	if err := handle.Cancel(); err != nil {
+		t.Fatal(err)
+	}
+	server.mutex.Lock()
+	cancelled := server.cancelled
+	server.mutex.Unlock()
+	if !reflect.DeepEqual(cancelled, []interface{}{"ctx"}) {
+		t.Errorf("Expected monitor_cancel for ctx, got %v", cancelled)
+	}
+	if len(ovs.monitors) != 0 {
+		t.Errorf("Expected no active monitors, got %d", len(ovs.monitors))
+	}
+}
+
+type updateHandler struct {
+	*disconnectHandler
+	updates chan TableUpdates
+}
+
+func (h *updateHandler) Update(context interface{}, tableUpdates TableUpdates) {
+	h.updates <- tableUpdates
+}
+
+type update2Handler struct {
+	*disconnectHandler
+	updates chan TableUpdates2
+}
+
+func (h *update2Handler) Update(context interface{}, tableUpdates TableUpdates) {}
+
+func (h *update2Handler) Update2(context interface{}, tableUpdates TableUpdates2) {
+	h.updates <- tableUpdates
+}
+
+func TestMonitorCond(t *testing.T) {
+	ovs, server, _ := newTestClient(t)
+	defer ovs.Disconnect()
+	handler := &updateHandler{newDisconnectHandler(), make(chan TableUpdates, 1)}
+	ovs.Register(handler)
+	handler2 := &update2Handler{newDisconnectHandler(), make(chan TableUpdates2, 1)}
+	ovs.Register(handler2)
+
+	requests := map[string]MonitorCondRequest{"Bridge": {
+		MonitorRequest: MonitorRequest{Columns: []string{"name"}},
+		Where:          [][]interface{}{NewCondition("name", "==", "br0")},
+	}}
+	handle, updates, err := ovs.MonitorCond("Open_vSwitch", "ctx", requests)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name := updates["Bridge"]["a"]["initial"].Fields["name"]; name != "br0" {
+		t.Errorf("Expected initial row br0, got %v", name)
+	}
+	if !reflect.DeepEqual(handle.CondRequests(), requests) {
+		t.Errorf("Unexpected handle requests %v", handle.CondRequests())
+	}
+
+	// The server sends further changes with update2
+	err = server.notify("update2", []interface{}{"ctx", map[string]interface{}{"Bridge": map[string]interface{}{
+		"a": map[string]interface{}{"modify": map[string]interface{}{"name": "br1"}},
+		"b": map[string]interface{}{"insert": map[string]interface{}{"name": "br0"}},
+	}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case updates := <-handler2.updates:
+		if name := updates["Bridge"]["a"]["modify"].Fields["name"]; name != "br1" {
+			t.Errorf("Expected the row-diff of the modified row, got %v", updates["Bridge"]["a"])
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("update2 notification not received by the Update2Handler")
+	}
+	select {
+	case updates := <-handler.updates:
+		rows := updates.Updates["Bridge"].Rows
+		if _, ok := rows["a"]; ok {
+			t.Errorf("Expected the modified row to be left out, got %v", rows["a"])
+		}
+		if name := rows["b"].New.Fields["name"]; name != "br0" {
+			t.Errorf("Expected inserted row br0, got %v", name)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("update2 notification not received by the Update handler")
+	}
+}
+
+type lockHandler struct {
+	*disconnectHandler
+	locked chan []interface{}
+	stolen chan []interface{}
+}
+
+func (h *lockHandler) Locked(params []interface{}) {
+	h.locked <- params
+}
+
+func (h *lockHandler) Stolen(params []interface{}) {
+	h.stolen <- params
+}
+
+func TestLock(t *testing.T) {
+	ovs, server, _ := newTestClient(t)
+	defer ovs.Disconnect()
+	handler := &lockHandler{newDisconnectHandler(), make(chan []interface{}, 1), make(chan []interface{}, 1)}
+	ovs.Register(handler)
+
+	locked, err := ovs.Lock("mylock")
+	if err != nil || !locked {
+		t.Fatalf("Expected the lock to be granted, got %v, %v", locked, err)
+	}
+	locked, err = ovs.Lock("mylock")
+	if err != nil || locked {
+		t.Fatalf("Expected the lock to be pending, got %v, %v", locked, err)
+	}
+	if err := ovs.Unlock("mylock"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ovs.Steal("mylock"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := server.notify("locked", []interface{}{"mylock"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := server.notify("stolen", []interface{}{"mylock"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, ch := range []chan []interface{}{handler.locked, handler.stolen} {
+		select {
+		case params := <-ch:
+			if !reflect.DeepEqual(params, []interface{}{"mylock"}) {
+				t.Errorf("Unexpected notification params %v", params)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("Lock notification not received")
+		}
+	}
+}
+
+func TestMonitorCondSince(t *testing.T) {
+	server := &testServer{}
+	ln, conns := server.listen(t)
+	defer ln.Close()
+
+	ovs, err := ConnectWithOptions("tcp:"+ln.Addr().String(), nil, &ConnectOptions{
+		Reconnect:      true,
+		InitialBackoff: 10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ovs.Disconnect()
+	handler := &reconnectHandler{
+		disconnectHandler: newDisconnectHandler(),
+		updates:           make(chan interface{}, 1),
+		reconnected:       make(chan *OvsdbClient, 1),
+	}
+	ovs.Register(handler)
+
+	requests := map[string]MonitorCondRequest{"Bridge": {MonitorRequest: MonitorRequest{Columns: []string{"name"}}}}
+	handle, found, _, err := ovs.MonitorCondSince("Open_vSwitch", "ctx", "", requests)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found || handle.LastTxnID() != "txn1" {
+		t.Errorf("Unexpected reply found=%v lastTxnID=%s", found, handle.LastTxnID())
+	}
+
+	err = server.notify("update3", []interface{}{"ctx", "txn2", map[string]interface{}{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-handler.updates
+	if handle.LastTxnID() != "txn2" {
+		t.Errorf("Expected lastTxnID txn2, got %s", handle.LastTxnID())
+	}
+
+	// The monitor is resumed from the last transaction on reconnection
+	(<-conns).Close()
+	handler.wait(t)
+	select {
+	case <-handler.reconnected:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Reconnected notification not received")
+	}
+	server.mutex.Lock()
+	txnIDs := server.txnIDs
+	server.mutex.Unlock()
+	if !reflect.DeepEqual(txnIDs, []interface{}{zeroTxnID, "txn2"}) {
+		t.Errorf("Unexpected monitor_cond_since requests %v", txnIDs)
+	}
+}
+
+func TestEchoKeepalive(t *testing.T) {
+	clientConn, serverConn := net.Pipe()
+	server := &testServer{}
+	server.serve(serverConn)
+	ovs := newOvsdbClient("", nil, &ConnectOptions{EchoInterval: 10 * time.Millisecond})
+	handler := newDisconnectHandler()
+	ovs.Register(handler)
+	if err := ovs.attach(context.Background(), clientConn); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-handler.disconnected:
+		t.Fatalf("Unexpected disconnection: %v", ovs.DisconnectReason())
+	case <-time.After(100 * time.Millisecond):
+	}
+	server.mutex.Lock()
+	echoes := server.echoes
+	server.mutex.Unlock()
+	if echoes == 0 {
+		t.Error("Expected echo requests to be sent")
+	}
+	ovs.Disconnect()
+	handler.wait(t)
+}
+
+func TestEchoTimeout(t *testing.T) {
+	clientConn, serverConn := net.Pipe()
+	server := &testServer{deaf: make(chan struct{})}
+	defer close(server.deaf)
+	server.serve(serverConn)
+	ovs := newOvsdbClient("", nil, &ConnectOptions{EchoInterval: 10 * time.Millisecond})
+	handler := newDisconnectHandler()
+	ovs.Register(handler)
+	if err := ovs.attach(context.Background(), clientConn); err != nil {
+		t.Fatal(err)
+	}
+
+	handler.wait(t)
+	if err := ovs.DisconnectReason(); err != ErrEchoTimeout {
+		t.Errorf("Expected %v, got %v", ErrEchoTimeout, err)
+	}
+}
+
+type slowHandler struct {
+	*disconnectHandler
+	delay time.Duration
+	done  chan struct{}
+}
+
+func (h *slowHandler) Update(context interface{}, tableUpdates TableUpdates) {
+	time.Sleep(h.delay)
+	h.done <- struct{}{}
+}
+
+func TestEchoSlowHandler(t *testing.T) {
+	clientConn, serverConn := net.Pipe()
+	server := &testServer{}
+	server.serve(serverConn)
+	ovs := newOvsdbClient("", nil, &ConnectOptions{EchoInterval: 10 * time.Millisecond})
+	handler := &slowHandler{newDisconnectHandler(), 200 * time.Millisecond, make(chan struct{}, 1)}
+	ovs.Register(handler)
+	if err := ovs.attach(context.Background(), clientConn); err != nil {
+		t.Fatal(err)
+	}
+
+	// The handler holds up the read loop for much longer than EchoTimeout
+	err := server.notify("update", []interface{}{"ctx", map[string]interface{}{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-handler.done:
+	case <-handler.disconnected:
+		t.Fatalf("Unexpected disconnection: %v", ovs.DisconnectReason())
+	case <-time.After(5 * time.Second):
+		t.Fatal("update notification not received")
+	}
+	select {
+	case <-handler.disconnected:
+		t.Fatalf("Unexpected disconnection: %v", ovs.DisconnectReason())
+	case <-time.After(50 * time.Millisecond):
+	}
+	ovs.Disconnect()
+	handler.wait(t)
+}
+
+func TestConnectDialer(t *testing.T) {
+	server := &testServer{}
+	var network, addr string
+	dialer := func(ctx context.Context, n, a string) (net.Conn, error) {
+		network, addr = n, a
+		clientConn, serverConn := net.Pipe()
+		server.serve(serverConn)
+		return clientConn, nil
+	}
+	ovs, err := ConnectWithOptions("tcp:192.0.2.1:6640", nil, &ConnectOptions{Dialer: dialer})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ovs.Disconnect()
+
+	if network != "tcp" || addr != "192.0.2.1:6640" {
+		t.Errorf("Expected dial of tcp 192.0.2.1:6640, got %s %s", network, addr)
+	}
+	if _, ok := ovs.Schema["Open_vSwitch"]; !ok {
+		t.Error("Expected the schema to be fetched through the custom dialer")
+	}
+}
+
+func TestParseEndpoint(t *testing.T) {
+	tests := []struct {
+		endpoint string
+		scheme   string
+		addr     string
+		err      bool
+	}{
+		{"tcp:127.0.0.1:6641", TCP, "127.0.0.1:6641", false},
+		{"tcp:127.0.0.1", TCP, "127.0.0.1:6640", false},
+		{"tcp:", TCP, defaultTCPAddress, false},
+		{"tcp:[fe80::1]:6641", TCP, "[fe80::1]:6641", false},
+		{"tcp:[fe80::1]", TCP, "[fe80::1]:6640", false},
+		{"tcp:fe80::1", "", "", true},
+		{"ssl:ovsdb.example.com:6641", SSL, "ovsdb.example.com:6641", false},
+		{"ssl:ovsdb.example.com", SSL, "ovsdb.example.com:6640", false},
+		{"ssl:[::1]:6641", SSL, "[::1]:6641", false},
+		{"unix:/tmp/db.sock", UNIX, "/tmp/db.sock", false},
+		{"unix:", UNIX, defaultUnixAddress, false},
+		{"udp:127.0.0.1:6640", "", "", true},
+	}
+	for _, test := range tests {
+		scheme, addr, err := parseEndpoint(test.endpoint)
+		if test.err {
+			if err == nil {
+				t.Errorf("Expected an error parsing %s", test.endpoint)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Unexpected error parsing %s: %v", test.endpoint, err)
+			continue
+		}
+		if scheme != test.scheme || addr != test.addr {
+			t.Errorf("Expected %s %s for %s, got %s %s", test.scheme, test.addr, test.endpoint, scheme, addr)
+		}
+	}
+}
+
+func TestDisconnectTwice(t *testing.T) {
+	ovs, _, _ := newTestClient(t)
+	handler := newDisconnectHandler()
+	ovs.Register(handler)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ovs.Disconnect()
+		}()
+	}
+	wg.Wait()
+	ovs.Disconnect()
+
+	handler.wait(t)
+	select {
+	case <-handler.disconnected:
+		t.Error("Disconnected notified more than once")
+	case <-time.After(50 * time.Millisecond):
+	}
+	if err := ovs.DisconnectReason(); err != nil {
+		t.Errorf("Expected no disconnect reason, got %v", err)
+	}
+
+	// A client that never connected has nothing to close
+	newOvsdbClient("", nil, nil).Disconnect()
+}
+
+func TestConnectDialTimeout(t *testing.T) {
+	var mutex sync.Mutex
+	var dialed []string
+	dialer := func(ctx context.Context, network, addr string) (net.Conn, error) {
+		mutex.Lock()
+		dialed = append(dialed, addr)
+		mutex.Unlock()
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	options := &ConnectOptions{Dialer: dialer, DialTimeout: 20 * time.Millisecond}
+	_, err := ConnectWithOptions("tcp:192.0.2.1:6640,ssl:192.0.2.2:6640", nil, options)
+	if err == nil {
+		t.Fatal("Expected the connection to time out")
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	if !reflect.DeepEqual(dialed, []string{"192.0.2.1:6640", "192.0.2.2:6640"}) {
+		t.Errorf("Expected every endpoint to be tried, got %v", dialed)
+	}
+}
+
+func TestConnectParallel(t *testing.T) {
+	var mutex sync.Mutex
+	servers := make(map[string]*rpc2.Client)
+	dialer := func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if addr == "192.0.2.1:6640" {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		clientConn, serverConn := net.Pipe()
+		mutex.Lock()
+		servers[addr] = (&testServer{}).serve(serverConn)
+		mutex.Unlock()
+		return clientConn, nil
+	}
+	endpoints := "tcp:192.0.2.1:6640,tcp:192.0.2.2:6640,tcp:192.0.2.3:6640"
+	options := &ConnectOptions{Dialer: dialer, ParallelDial: true, Database: "Open_vSwitch"}
+	ovs, err := ConnectWithOptions(endpoints, nil, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ovs.Disconnect()
+
+	mutex.Lock()
+	dialed := servers
+	servers = make(map[string]*rpc2.Client)
+	mutex.Unlock()
+	if len(dialed) != 2 {
+		t.Fatalf("Expected 2 connections, got %d", len(dialed))
+	}
+	closed := 0
+	for _, server := range dialed {
+		select {
+		case <-server.DisconnectNotify():
+			closed++
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+	if closed != 1 {
+		t.Errorf("Expected 1 losing connection to be closed, got %d", closed)
+	}
+
+	options.Database = "OVN_Northbound"
+	options.DialTimeout = 20 * time.Millisecond
+	if _, err := ConnectWithOptions(endpoints, nil, options); err == nil {
+		t.Error("Expected an error connecting to a missing database")
+	}
+}
+
+func TestMonitorUnknownTable(t *testing.T) {
+	ovs, server, _ := newTestClient(t)
+	defer ovs.Disconnect()
+
+	requests := map[string]MonitorRequest{"Brige": {Select: MonitorAllSelect()}}
+	if _, _, err := ovs.Monitor("Open_vSwitch", "typo", requests); err == nil || !strings.Contains(err.Error(), `"Brige"`) {
+		t.Errorf("Expected an error naming the unknown table, got %v", err)
+	}
+	requests = map[string]MonitorRequest{"Bridge": {Columns: []string{"nmae"}, Select: MonitorAllSelect()}}
+	if _, _, err := ovs.Monitor("Open_vSwitch", "typo", requests); err == nil || !strings.Contains(err.Error(), `"nmae"`) {
+		t.Errorf("Expected an error naming the unknown column, got %v", err)
+	}
+	condRequests := map[string]MonitorCondRequest{"Brige": {MonitorRequest: MonitorRequest{Select: MonitorAllSelect()}}}
+	if _, _, err := ovs.MonitorCond("Open_vSwitch", "typo", condRequests); err == nil {
+		t.Error("Expected an error monitoring an unknown table")
+	}
+	function := "="
+	condRequests = map[string]MonitorCondRequest{"Bridge": {
+		MonitorRequest: MonitorRequest{Columns: []string{"name"}},
+		Where:          [][]interface{}{NewCondition("name", function, "br0")},
+	}}
+	if _, _, err := ovs.MonitorCond("Open_vSwitch", "typo", condRequests); err == nil || !strings.Contains(err.Error(), `"="`) {
+		t.Errorf("Expected an error naming the invalid function, got %v", err)
+	}
+	if contexts := server.monitorContexts(); len(contexts) != 0 {
+		t.Errorf("Expected no monitor to be sent, got %v", contexts)
+	}
+}
+
+func TestCall(t *testing.T) {
+	ovs, _, _ := newTestClient(t)
+	defer ovs.Disconnect()
+
+	var reply string
+	if err := ovs.Call("get_server_id", []interface{}{"Open_vSwitch"}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if reply != "server-id-Open_vSwitch" {
+		t.Errorf("Expected server-id-Open_vSwitch, got %s", reply)
+	}
+	if err := ovs.Call("get_server_id", []interface{}{}, &reply); err == nil {
+		t.Error("Expected the server error to be returned")
+	}
+}
+
+func TestReconnectOverlappingMonitors(t *testing.T) {
+	server := &testServer{}
+	ln, conns := server.listen(t)
+	defer ln.Close()
+
+	ovs, err := ConnectWithOptions("tcp:"+ln.Addr().String(), nil, &ConnectOptions{
+		Reconnect:      true,
+		InitialBackoff: 10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ovs.Disconnect()
+	handler := &reconnectHandler{
+		disconnectHandler: newDisconnectHandler(),
+		updates:           make(chan interface{}, 2),
+		reconnected:       make(chan *OvsdbClient, 1),
+	}
+	ovs.Register(handler)
+
+	// Two monitors on the same table, told apart by their context
+	hot := map[string]MonitorRequest{"Bridge": {Columns: []string{"name"}, Select: MonitorChangesOnly()}}
+	cold := map[string]MonitorRequest{"Bridge": {Columns: []string{"external_ids"}, Select: MonitorAllSelect()}}
+	hotHandle, _, err := ovs.Monitor("Open_vSwitch", "hot", hot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	coldHandle, _, err := ovs.Monitor("Open_vSwitch", "cold", cold)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	(<-conns).Close()
+	handler.wait(t)
+	select {
+	case <-handler.reconnected:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Reconnected notification not received")
+	}
+
+	expected := []interface{}{"hot", "cold", "hot", "cold"}
+	if contexts := server.monitorContexts(); !reflect.DeepEqual(contexts, expected) {
+		t.Errorf("Expected both monitors to be re-issued, got %v", contexts)
+	}
+	if !reflect.DeepEqual(hotHandle.Requests(), hot) || !reflect.DeepEqual(coldHandle.Requests(), cold) {
+		t.Error("Expected each monitor to keep its own requests")
+	}
+}
+
+func TestMonitorAllSkipsTablesWithoutColumns(t *testing.T) {
+	ovs, server, _ := newTestClient(t)
+	defer ovs.Disconnect()
+
+	schema := ovs.Schema["Open_vSwitch"]
+	if skipped := schema.SkippedTables(); !reflect.DeepEqual(skipped, []string{"Empty"}) {
+		t.Errorf("Expected the Empty table to be skipped, got %v", skipped)
+	}
+	if schema.Table("Empty") == nil {
+		t.Error("Expected the Empty table to be kept in the schema")
+	}
+	operation := Operation{Op: "insert", Table: "Empty", Row: map[string]interface{}{}}
+	if _, err := ovs.Transact("Open_vSwitch", operation); err == nil || !strings.Contains(err.Error(), "no columns") {
+		t.Errorf("Expected an error saying the table has no columns, got %v", err)
+	}
+
+	if _, _, err := ovs.MonitorAll("Open_vSwitch", "all"); err != nil {
+		t.Fatal(err)
+	}
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	requests := server.requests[0].(map[string]interface{})
+	if _, ok := requests["Empty"]; ok {
+		t.Error("Expected the table without columns to be skipped")
+	}
+	if _, ok := requests["Bridge"]; !ok {
+		t.Error("Expected the Bridge table to be monitored")
+	}
+}
+
+func TestTransactCtxLateReply(t *testing.T) {
+	ovs, server, _ := newTestClient(t)
+	defer ovs.Disconnect()
+	operation := Operation{Op: "insert", Table: "Bridge", Row: map[string]interface{}{"name": "br0"}}
+
+	server.stall = make(chan struct{})
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 1)
+	go func() {
+		_, err := ovs.TransactCtx(ctx, "Open_vSwitch", operation)
+		errs <- err
+	}()
+	cancel()
+	select {
+	case err := <-errs:
+		if err != context.Canceled {
+			t.Errorf("Expected %v, got %v", context.Canceled, err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("TransactCtx did not return after cancellation")
+	}
+
+	// The late reply to the abandoned transaction must be dropped without
+	// blocking the connection or being taken for another call's reply
+	close(server.stall)
+	results, err := ovs.TransactCtx(context.Background(), "Open_vSwitch", operation)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 {
+		t.Errorf("Expected 1 result, got %d", len(results))
+	}
+}
+
+// reentrantHandler replaces itself with next on its first update
+type reentrantHandler struct {
+	*disconnectHandler
+	ovs     *OvsdbClient
+	next    NotificationHandler
+	updates chan interface{}
+}
+
+func (h *reentrantHandler) Update(context interface{}, tableUpdates TableUpdates) {
+	h.ovs.Unregister(h)
+	h.ovs.Register(h.next)
+	h.updates <- context
+}
+
+func TestRegisterFromHandler(t *testing.T) {
+	ovs, server, _ := newTestClient(t)
+	next := &updateHandler{newDisconnectHandler(), make(chan TableUpdates, 1)}
+	handler := &reentrantHandler{newDisconnectHandler(), ovs, next, make(chan interface{}, 2)}
+	ovs.Register(handler)
+
+	for i := 0; i < 2; i++ {
+		if err := server.notify("update", []interface{}{"ctx", map[string]interface{}{}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	select {
+	case <-handler.updates:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Update not received, handler deadlocked")
+	}
+	select {
+	case <-next.updates:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Update not received by the handler registered during dispatch")
+	}
+	if len(handler.updates) != 0 {
+		t.Error("Expected the unregistered handler to get no more updates")
+	}
+
+	ovs.Disconnect()
+	next.wait(t)
+}
+
+func TestDisconnectedOnceAfterServerClose(t *testing.T) {
+	ovs, _, serverConn := newTestClient(t)
+	handler := newDisconnectHandler()
+	ovs.Register(handler)
+
+	serverConn.Close()
+	handler.wait(t)
+	ovs.Disconnect()
+	ovs.Disconnect()
+	select {
+	case <-handler.disconnected:
+		t.Error("Disconnected notified more than once")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestMonitorAllExcept(t *testing.T) {
+	ovs, server, _ := newTestClient(t)
+	defer ovs.Disconnect()
+
+	schema := ovs.Schema["Open_vSwitch"]
+	schema.Tables = map[string]TableSchema{
+		"Bridge": schema.Tables["Bridge"],
+		"Port":   {Columns: map[string]ColumnSchema{"name": {Type: "string"}}},
+	}
+	ovs.Schema["Open_vSwitch"] = schema
+
+	exclude := map[string][]string{"Bridge": {"external_ids"}, "Port": {}}
+	if _, _, err := ovs.MonitorAllExcept("Open_vSwitch", "except", exclude); err != nil {
+		t.Fatal(err)
+	}
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	requests := server.requests[0].(map[string]interface{})
+	if _, ok := requests["Port"]; ok {
+		t.Error("Expected the Port table to be excluded")
+	}
+	bridge, ok := requests["Bridge"].(map[string]interface{})
+	if !ok {
+		t.Fatal("Expected the Bridge table to be monitored")
+	}
+	if columns := bridge["columns"]; !reflect.DeepEqual(columns, []interface{}{"name"}) {
+		t.Errorf("Expected only the name column to be monitored, got %v", columns)
+	}
+}
+
+func TestTransactAndCheck(t *testing.T) {
+	ovs, _, _ := newTestClient(t)
+	defer ovs.Disconnect()
+	operation := Operation{Op: "insert", Table: "Bridge", Row: map[string]interface{}{"name": "br0"}}
+
+	if _, err := ovs.TransactAndCheck("Open_vSwitch", operation); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := ovs.TransactAndCheck("Open_vSwitch", operation, NewAbortOperation(), operation)
+	if len(results) != 3 {
+		t.Errorf("Expected 3 results, got %d", len(results))
+	}
+	txnErr, ok := err.(*TransactionError)
+	if !ok {
+		t.Fatalf("Expected a *TransactionError, got %v", err)
+	}
+	expected := []OperationError{{Index: 1, Error: "aborted", Details: "aborted by request"}}
+	if !reflect.DeepEqual(txnErr.Errors, expected) {
+		t.Errorf("Expected %v, got %v", expected, txnErr.Errors)
+	}
+	if msg := err.Error(); msg != "transaction failed: operation 1: aborted: aborted by request" {
+		t.Errorf("Unexpected error message %q", msg)
+	}
+}
@@ -17,7 +17,10 @@
 package libovsdb
 
 import (
+	"context"
 	"crypto/tls"
+	"net"
+	"time"
 )
 
 // Config is a structure used in provisioning a connection to ovsdb.
@@ -25,3 +28,91 @@ type Config struct {
 	Addr      string
 	TLSConfig *tls.Config
 }
+
+const (
+	defaultInitialBackoff = time.Second
+	defaultMaxBackoff     = 30 * time.Second
+	defaultEchoInterval   = 5 * time.Second
+	defaultDialTimeout    = 30 * time.Second
+)
+
+// DialFunc establishes the transport connection to an endpoint, network and
+// addr are as in net.Dial
+type DialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// ConnectOptions are the options used by ConnectWithOptions
+type ConnectOptions struct {
+	// Reconnect redials the endpoints when the connection to the server is lost,
+	// fetching the schemas again and re-issuing the active monitors
+	Reconnect bool
+	// MaxRetries is the number of reconnection attempts, 0 means no limit
+	MaxRetries int
+	// InitialBackoff is the delay before the first reconnection attempt, it is
+	// doubled after every failed attempt. Defaults to 1s
+	InitialBackoff time.Duration
+	// MaxBackoff is the maximum delay between reconnection attempts. Defaults to 30s
+	MaxBackoff time.Duration
+	// EchoInterval is how long the server may stay silent before an echo
+	// request is sent to check it is alive. Any message from the server counts,
+	// and so does a notification still being handled, since handlers hold up
+	// reading the reply. Defaults to 5s, a negative value disables echoes
+	EchoInterval time.Duration
+	// EchoTimeout is how long to wait for a message after an echo request
+	// before dropping the connection. Defaults to EchoInterval
+	EchoTimeout time.Duration
+	// DialTimeout bounds the time spent connecting to each endpoint, including
+	// the TLS handshake. Defaults to 30s
+	DialTimeout time.Duration
+	// Database, if set, makes connecting fail when the server does not serve it
+	Database string
+	// ParallelDial dials all the endpoints at once instead of one after the
+	// other, keeping the first connection that serves Database. Reconnections
+	// still dial the endpoints in order
+	ParallelDial bool
+	// Dialer replaces the built-in dialer, e.g. to go through a proxy. For ssl
+	// endpoints the TLS handshake is done over the connection it returns
+	Dialer DialFunc
+}
+
+func (o ConnectOptions) initialBackoff() time.Duration {
+	if o.InitialBackoff <= 0 {
+		return defaultInitialBackoff
+	}
+	return o.InitialBackoff
+}
+
+func (o ConnectOptions) maxBackoff() time.Duration {
+	if o.MaxBackoff <= 0 {
+		return defaultMaxBackoff
+	}
+	return o.MaxBackoff
+}
+
+func (o ConnectOptions) echoInterval() time.Duration {
+	if o.EchoInterval == 0 {
+		return defaultEchoInterval
+	}
+	return o.EchoInterval
+}
+
+func (o ConnectOptions) echoTimeout() time.Duration {
+	if o.EchoTimeout <= 0 {
+		return o.echoInterval()
+	}
+	return o.EchoTimeout
+}
+
+func (o ConnectOptions) dialTimeout() time.Duration {
+	if o.DialTimeout <= 0 {
+		return defaultDialTimeout
+	}
+	return o.DialTimeout
+}
+
+func (o ConnectOptions) dialer() DialFunc {
+	if o.Dialer == nil {
+		var dialer net.Dialer
+		return dialer.DialContext
+	}
+	return o.Dialer
+}
@@ -141,7 +141,7 @@ func main() {
 	var notifier myNotifier
 	ovs.Register(notifier)
 
-	initial, _ := ovs.MonitorAll("Open_vSwitch", "")
+	_, initial, _ := ovs.MonitorAll("Open_vSwitch", "")
 	populateCache(*initial)
 
 	fmt.Println(`Silly game of stopping this app when a Bridge with name "stop" is monitored !`)
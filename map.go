@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"reflect"
+	"sort"
 )
 
 // OvsMap is the JSON map structure used for OVSDB
@@ -18,14 +19,26 @@ type OvsMap struct {
 }
 
 // MarshalJSON marshalls an OVSDB style Map to a byte array
+// Pairs are sorted by their encoded key so equal maps marshal identically
 func (o OvsMap) MarshalJSON() ([]byte, error) {
+	type pair struct {
+		key  string
+		elem []interface{}
+	}
+	pairs := make([]pair, 0, len(o.GoMap))
+	for key, val := range o.GoMap {
+		encoded, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, pair{string(encoded), []interface{}{key, val}})
+	}
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i].key < pairs[j].key })
+
 	var ovsMap, innerMap []interface{}
 	ovsMap = append(ovsMap, "map")
-	for key, val := range o.GoMap {
-		var mapSeg []interface{}
-		mapSeg = append(mapSeg, key)
-		mapSeg = append(mapSeg, val)
-		innerMap = append(innerMap, mapSeg)
+	for _, p := range pairs {
+		innerMap = append(innerMap, p.elem)
 	}
 	ovsMap = append(ovsMap, innerMap)
 	return json.Marshal(ovsMap)
@@ -39,7 +52,16 @@ func (o *OvsMap) UnmarshalJSON(b []byte) (err error) {
 		innerSlice := oMap[1].([]interface{})
 		for _, val := range innerSlice {
 			f := val.([]interface{})
-			o.GoMap[f[0]] = f[1]
+			// uuid keys and values are 2-element arrays, which can't be map keys
+			key, err := ovsSliceToGoNotation(f[0])
+			if err != nil {
+				return err
+			}
+			value, err := ovsSliceToGoNotation(f[1])
+			if err != nil {
+				return err
+			}
+			o.GoMap[key] = value
 		}
 	}
 	return err
@@ -1,11 +1,14 @@
 package libovsdb
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"fmt"
+)
 
 // Operation represents an operation according to RFC7047 section 5.2
 type Operation struct {
 	Op        string                   `json:"op"`
-	Table     string                   `json:"table"`
+	Table     string                   `json:"table,omitempty"`
 	Row       map[string]interface{}   `json:"row,omitempty"`
 	Rows      []map[string]interface{} `json:"rows,omitempty"`
 	Columns   []string                 `json:"columns,omitempty"`
@@ -14,15 +17,18 @@ type Operation struct {
 	Where     []interface{}            `json:"where,omitempty"`
 	Until     string                   `json:"until,omitempty"`
 	UUIDName  string                   `json:"uuid-name,omitempty"`
+	Comment   string                   `json:"comment,omitempty"`
+	Lock      string                   `json:"lock,omitempty"`
 }
 
 // MarshalJSON marshalls 'Operation' to a byte array
-// For 'select' operations, we dont omit the 'Where' field
-// to allow selecting all rows of a table
+// For 'select', 'update', 'mutate' and 'delete' operations, we dont omit
+// the 'Where' field, which RFC7047 requires, to allow matching all rows
+// of a table
 func (o Operation) MarshalJSON() ([]byte, error) {
 	type OpAlias Operation
 	switch o.Op {
-	case "select":
+	case "select", "update", "mutate", "delete":
 		where := o.Where
 		if where == nil {
 			where = make([]interface{}, 0, 0)
@@ -34,6 +40,28 @@ func (o Operation) MarshalJSON() ([]byte, error) {
 			Where:   where,
 			OpAlias: (OpAlias)(o),
 		})
+	case "wait":
+		// A wait always has a where and rows, and a missing timeout
+		// would make the server wait forever
+		where := o.Where
+		if where == nil {
+			where = make([]interface{}, 0, 0)
+		}
+		rows := o.Rows
+		if rows == nil {
+			rows = make([]map[string]interface{}, 0, 0)
+		}
+		return json.Marshal(&struct {
+			Where   []interface{}            `json:"where"`
+			Rows    []map[string]interface{} `json:"rows"`
+			Timeout int                      `json:"timeout"`
+			OpAlias
+		}{
+			Where:   where,
+			Rows:    rows,
+			Timeout: o.Timeout,
+			OpAlias: (OpAlias)(o),
+		})
 	default:
 		return json.Marshal(&struct {
 			OpAlias
@@ -57,6 +85,14 @@ type MonitorRequest struct {
 	Select  MonitorSelect `json:"select,omitempty"`
 }
 
+// MonitorCondRequest represents a monitor request with conditions,
+// according to the monitor_cond extension of ovsdb-server(7).
+// Only rows matching every condition in Where are monitored
+type MonitorCondRequest struct {
+	MonitorRequest
+	Where [][]interface{} `json:"where,omitempty"`
+}
+
 // MonitorSelect represents a monitor select according to RFC7047
 type MonitorSelect struct {
 	Initial bool `json:"initial,omitempty"`
@@ -65,6 +101,22 @@ type MonitorSelect struct {
 	Modify  bool `json:"modify,omitempty"`
 }
 
+// MonitorAllSelect selects the initial rows and every change to them
+func MonitorAllSelect() MonitorSelect {
+	return MonitorSelect{Initial: true, Insert: true, Delete: true, Modify: true}
+}
+
+// MonitorChangesOnly selects inserted, deleted and modified rows but not the
+// initial contents of the table
+func MonitorChangesOnly() MonitorSelect {
+	return MonitorSelect{Insert: true, Delete: true, Modify: true}
+}
+
+// MonitorInitialOnly selects only the initial contents of the table
+func MonitorInitialOnly() MonitorSelect {
+	return MonitorSelect{Initial: true}
+}
+
 // TableUpdates is a collection of TableUpdate entries
 // We cannot use TableUpdates directly by json encoding by inlining the TableUpdate Map
 // structure till GoLang issue #6213 makes it.
@@ -85,22 +137,141 @@ type RowUpdate struct {
 	Old Row `json:"old,omitempty"`
 }
 
+// RowUpdate2 represents a row update according to the monitor_cond extension
+// of ovsdb-server(7). It holds exactly one of the "initial", "insert",
+// "delete" or "modify" members. Deleted rows have no contents and modify
+// carries a row-diff with the modified columns only.
+// In a row-diff, set columns hold the elements to add or remove: elements
+// already in the set are removed and the others added. Map columns hold the
+// pairs to add, update or remove: a missing key is added, a key with another
+// value is updated and a key with the same value is removed. Other columns
+// hold their new value
+type RowUpdate2 map[string]Row
+
+// TableUpdates2 is a <table-updates2> of the monitor_cond extension of
+// ovsdb-server(7): the RowUpdate2 of each updated row, per table and row UUID
+type TableUpdates2 map[string]map[string]RowUpdate2
+
 // OvsdbError is an OVS Error Condition
 type OvsdbError struct {
 	Error   string `json:"error"`
 	Details string `json:"details,omitempty"`
 }
 
-// NewCondition creates a new condition as specified in RFC7047
+// NewSelectOperation creates a select operation returning the given columns,
+// or all of them if there are none, of the rows of table matching every
+// condition in where
+func NewSelectOperation(table string, columns []string, where ...[]interface{}) Operation {
+	return Operation{Op: "select", Table: table, Columns: columns, Where: conditions(where)}
+}
+
+// NewWaitOperation creates a wait operation, which waits up to timeout
+// milliseconds until the given columns of the rows of table matching every
+// condition in where are equal ("==") or not equal ("!=") to rows. The
+// transaction fails if the timeout expires, immediately if it is 0
+func NewWaitOperation(table string, timeout int, until string, columns []string, rows []map[string]interface{}, where ...[]interface{}) Operation {
+	return Operation{Op: "wait", Table: table, Timeout: timeout, Until: until, Columns: columns, Rows: rows, Where: conditions(where)}
+}
+
+// NewCommentOperation creates a comment operation, which adds comment to the
+// transaction log entry without affecting the database
+func NewCommentOperation(comment string) Operation {
+	return Operation{Op: "comment", Comment: comment}
+}
+
+// NewAbortOperation creates an abort operation, which makes the whole
+// transaction fail
+func NewAbortOperation() Operation {
+	return Operation{Op: "abort"}
+}
+
+// NewAssertOperation creates an assert operation, which makes the whole
+// transaction fail unless the client holds lock
+func NewAssertOperation(lock string) Operation {
+	return Operation{Op: "assert", Lock: lock}
+}
+
+// Function is the <function> of a condition according to RFC7047
+type Function string
+
+// The functions a condition can use
+const (
+	FunctionEqual              Function = "=="
+	FunctionNotEqual           Function = "!="
+	FunctionLessThan           Function = "<"
+	FunctionLessThanOrEqual    Function = "<="
+	FunctionGreaterThan        Function = ">"
+	FunctionGreaterThanOrEqual Function = ">="
+	FunctionIncludes           Function = "includes"
+	FunctionExcludes           Function = "excludes"
+)
+
+// Valid reports whether f is one of the functions defined by RFC7047
+func (f Function) Valid() bool {
+	switch f {
+	case FunctionEqual, FunctionNotEqual, FunctionLessThan, FunctionLessThanOrEqual,
+		FunctionGreaterThan, FunctionGreaterThanOrEqual, FunctionIncludes, FunctionExcludes:
+		return true
+	}
+	return false
+}
+
+// Mutator is the <mutator> of a mutation according to RFC7047
+type Mutator string
+
+// The mutators a mutation can use
+const (
+	MutatorAdd      Mutator = "+="
+	MutatorSubtract Mutator = "-="
+	MutatorMultiply Mutator = "*="
+	MutatorDivide   Mutator = "/="
+	MutatorModulo   Mutator = "%="
+	MutatorInsert   Mutator = "insert"
+	MutatorDelete   Mutator = "delete"
+)
+
+// Valid reports whether m is one of the mutators defined by RFC7047
+func (m Mutator) Valid() bool {
+	switch m {
+	case MutatorAdd, MutatorSubtract, MutatorMultiply, MutatorDivide,
+		MutatorModulo, MutatorInsert, MutatorDelete:
+		return true
+	}
+	return false
+}
+
+// NewCondition creates a new condition as specified in RFC7047. The function
+// is not checked here but by Transact and the MonitorCond calls, see
+// NewConditionChecked to check it right away
 func NewCondition(column string, function string, value interface{}) []interface{} {
 	return []interface{}{column, function, value}
 }
 
-// NewMutation creates a new mutation as specified in RFC7047
+// NewConditionChecked is like NewCondition but fails if function is not one
+// of the functions defined by RFC7047
+func NewConditionChecked(column string, function Function, value interface{}) ([]interface{}, error) {
+	if !function.Valid() {
+		return nil, fmt.Errorf("invalid function %q in condition on column %q", function, column)
+	}
+	return NewCondition(column, string(function), value), nil
+}
+
+// NewMutation creates a new mutation as specified in RFC7047. The mutator is
+// not checked here but by Transact, see NewMutationChecked to check it right
+// away
 func NewMutation(column string, mutator string, value interface{}) []interface{} {
 	return []interface{}{column, mutator, value}
 }
 
+// NewMutationChecked is like NewMutation but fails if mutator is not one of
+// the mutators defined by RFC7047
+func NewMutationChecked(column string, mutator Mutator, value interface{}) ([]interface{}, error) {
+	if !mutator.Valid() {
+		return nil, fmt.Errorf("invalid mutator %q in mutation on column %q", mutator, column)
+	}
+	return NewMutation(column, string(mutator), value), nil
+}
+
 // TransactResponse represents the response to a Transact Operation
 type TransactResponse struct {
 	Result []OperationResult `json:"result"`
@@ -143,5 +314,3 @@ func ovsSliceToGoNotation(val interface{}) (interface{}, error) {
 	}
 	return val, nil
 }
-
-// TODO : add Condition, Function, Mutation and Mutator notations
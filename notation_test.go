@@ -3,6 +3,7 @@ package libovsdb
 import (
 	"encoding/json"
 	"log"
+	"reflect"
 	"testing"
 )
 
@@ -94,10 +95,17 @@ func TestValidateOvsMap(t *testing.T) {
 	if err != nil {
 		t.Error("Error Marshalling OvsMap", err)
 	}
-	expected1 := `["map",[[1,"hello"],[2,"world"]]]`
-	expected2 := `["map",[[2,"world"],[1,"hello"]]]`
-	if string(data) != expected1 && string(data) != expected2 {
-		t.Error("Expected: ", expected1, "Got", string(data))
+	expected := `["map",[[1,"hello"],[2,"world"]]]`
+	if string(data) != expected {
+		t.Error("Expected: ", expected, "Got", string(data))
+	}
+	// Equal maps marshal identically whatever the iteration order
+	strMap, _ := NewOvsMap(map[string]string{"c": "3", "a": "1", "b": "2", "d": "4"})
+	for i := 0; i < 10; i++ {
+		data, _ = json.Marshal(strMap)
+		if string(data) != `["map",[["a","1"],["b","2"],["c","3"],["d","4"]]]` {
+			t.Error("OvsMap not marshalled in key order", string(data))
+		}
 	}
 	// Negative condition test
 	integer := 5
@@ -107,6 +115,57 @@ func TestValidateOvsMap(t *testing.T) {
 	}
 }
 
+func TestOvsMapUUIDRoundTrip(t *testing.T) {
+	port := UUID{GoUUID: "550e8400-e29b-41d4-a716-446655440000"}
+	queue := UUID{GoUUID: "550e8400-e29b-41d4-a716-446655440001"}
+	tests := []map[interface{}]interface{}{
+		{"port": port},
+		{float64(1): queue},
+		{port: "uplink"},
+		{port: queue},
+	}
+	for _, goMap := range tests {
+		data, err := json.Marshal(OvsMap{goMap})
+		if err != nil {
+			t.Fatal("Error Marshalling OvsMap", err)
+		}
+		var oMap OvsMap
+		if err := json.Unmarshal(data, &oMap); err != nil {
+			t.Fatal("Error Unmarshalling OvsMap", err)
+		}
+		if !reflect.DeepEqual(oMap.GoMap, goMap) {
+			t.Error("Expected: ", goMap, "Got", oMap.GoMap)
+		}
+	}
+}
+
+func TestOvsSetUnmarshalAtom(t *testing.T) {
+	port := UUID{GoUUID: "550e8400-e29b-41d4-a716-446655440000"}
+	tests := []struct {
+		data     string
+		expected []interface{}
+	}{
+		{`"secure"`, []interface{}{"secure"}},
+		{`["set",["secure"]]`, []interface{}{"secure"}},
+		{`["set",["standalone","secure"]]`, []interface{}{"standalone", "secure"}},
+		{`42`, []interface{}{float64(42)}},
+		{`true`, []interface{}{true}},
+		{`["uuid","550e8400-e29b-41d4-a716-446655440000"]`, []interface{}{port}},
+		{`["set",[["uuid","550e8400-e29b-41d4-a716-446655440000"]]]`, []interface{}{port}},
+		{`["set",[]]`, nil},
+	}
+	for _, test := range tests {
+		var oSet OvsSet
+		if err := json.Unmarshal([]byte(test.data), &oSet); err != nil {
+			t.Errorf("Error Unmarshalling %s: %v", test.data, err)
+			continue
+		}
+		if !reflect.DeepEqual(oSet.GoSet, test.expected) {
+			t.Error("Expected: ", test.expected, "Got", oSet.GoSet)
+		}
+	}
+}
+
 func TestValidateUuid(t *testing.T) {
 	uuid1 := UUID{"this is a bad uuid"}                   // Bad
 	uuid2 := UUID{"alsoabaduuid"}                         // Bad
@@ -165,6 +224,121 @@ func TestNewCondition(t *testing.T) {
 	}
 }
 
+func TestNewSelectOperation(t *testing.T) {
+	tests := []struct {
+		operation Operation
+		expected  string
+	}{
+		{NewSelectOperation("Bridge", nil), `{"where":[],"op":"select","table":"Bridge"}`},
+		{
+			NewSelectOperation("Bridge", []string{"_uuid", "name"},
+				NewCondition("name", "!=", "br0"), NewCondition("stp_enable", "==", true)),
+			`{"where":[["name","!=","br0"],["stp_enable","==",true]],"op":"select","table":"Bridge","columns":["_uuid","name"]}`,
+		},
+	}
+	for _, test := range tests {
+		str, err := json.Marshal(test.operation)
+		if err != nil {
+			t.Fatal("serialization error:", err)
+		}
+		if string(str) != test.expected {
+			t.Error("Expected: ", test.expected, "Got", string(str))
+		}
+	}
+}
+
+func TestNewWaitOperation(t *testing.T) {
+	tests := []struct {
+		operation Operation
+		expected  string
+	}{
+		{
+			NewWaitOperation("Bridge", 0, "==", []string{"name"}, nil, NewCondition("name", "==", "br0")),
+			`{"where":[["name","==","br0"]],"rows":[],"timeout":0,"op":"wait","table":"Bridge","columns":["name"],"until":"=="}`,
+		},
+		{
+			NewWaitOperation("Bridge", 500, "!=", []string{"name"}, []map[string]interface{}{{"name": "br0"}}),
+			`{"where":[],"rows":[{"name":"br0"}],"timeout":500,"op":"wait","table":"Bridge","columns":["name"],"until":"!="}`,
+		},
+	}
+	for _, test := range tests {
+		str, err := json.Marshal(test.operation)
+		if err != nil {
+			t.Fatal("serialization error:", err)
+		}
+		if string(str) != test.expected {
+			t.Error("Expected: ", test.expected, "Got", string(str))
+		}
+	}
+}
+
+func TestTablelessOperations(t *testing.T) {
+	tests := []struct {
+		operation Operation
+		expected  string
+	}{
+		{NewCommentOperation("added br0"), `{"op":"comment","comment":"added br0"}`},
+		{NewAbortOperation(), `{"op":"abort"}`},
+		{NewAssertOperation("my_lock"), `{"op":"assert","lock":"my_lock"}`},
+	}
+	for _, test := range tests {
+		str, err := json.Marshal(test.operation)
+		if err != nil {
+			t.Fatal("serialization error:", err)
+		}
+		if string(str) != test.expected {
+			t.Error("Expected: ", test.expected, "Got", string(str))
+		}
+		var operation Operation
+		if err := json.Unmarshal(str, &operation); err != nil {
+			t.Fatal("deserialization error:", err)
+		}
+		if !reflect.DeepEqual(operation, test.operation) {
+			t.Error("Expected: ", test.operation, "Got", operation)
+		}
+	}
+}
+
+func TestFunctionAndMutatorValid(t *testing.T) {
+	for _, f := range []Function{"==", "!=", "<", "<=", ">", ">=", "includes", "excludes"} {
+		if !f.Valid() {
+			t.Errorf("Expected function %s to be valid", f)
+		}
+	}
+	for _, f := range []Function{"=", "=~", "contains", ""} {
+		if f.Valid() {
+			t.Errorf("Expected function %s to be invalid", f)
+		}
+	}
+	for _, m := range []Mutator{"+=", "-=", "*=", "/=", "%=", "insert", "delete"} {
+		if !m.Valid() {
+			t.Errorf("Expected mutator %s to be valid", m)
+		}
+	}
+	for _, m := range []Mutator{"+", "=", "append", ""} {
+		if m.Valid() {
+			t.Errorf("Expected mutator %s to be invalid", m)
+		}
+	}
+}
+
+func TestNewConditionAndMutationChecked(t *testing.T) {
+	cond, err := NewConditionChecked("name", FunctionIncludes, "br0")
+	if err != nil || !reflect.DeepEqual(cond, []interface{}{"name", "includes", "br0"}) {
+		t.Errorf("Unexpected condition %v, error %v", cond, err)
+	}
+	if _, err := NewConditionChecked("name", "=", "br0"); err == nil {
+		t.Error("Expected an error for an invalid function")
+	}
+	mutation, err := NewMutationChecked("flood_vlans", MutatorDelete, 10)
+	if err != nil || !reflect.DeepEqual(mutation, []interface{}{"flood_vlans", "delete", 10}) {
+		t.Errorf("Unexpected mutation %v, error %v", mutation, err)
+	}
+	if _, err := NewMutationChecked("flood_vlans", "+", 10); err == nil {
+		t.Error("Expected an error for an invalid mutator")
+	}
+}
+
 func TestNewMutation(t *testing.T) {
 	mutation := NewMutation("column", "+=", 1)
 	mutationStr, _ := json.Marshal(mutation)
@@ -173,3 +347,20 @@ func TestNewMutation(t *testing.T) {
 		t.Error("mutation is not correctly formatted")
 	}
 }
+
+func TestMonitorSelectPresets(t *testing.T) {
+	tests := []struct {
+		sel      MonitorSelect
+		expected string
+	}{
+		{MonitorAllSelect(), `{"initial":true,"insert":true,"delete":true,"modify":true}`},
+		{MonitorChangesOnly(), `{"insert":true,"delete":true,"modify":true}`},
+		{MonitorInitialOnly(), `{"initial":true}`},
+	}
+	for _, test := range tests {
+		selStr, _ := json.Marshal(test.sel)
+		if string(selStr) != test.expected {
+			t.Error("Expected: ", test.expected, "Got", string(selStr))
+		}
+	}
+}
@@ -271,7 +271,7 @@ func TestMonitor(t *testing.T) {
 		t.Fatalf("Failed to Connect. error: %s", err)
 	}
 
-	reply, err := ovs.MonitorAll("Open_vSwitch", nil)
+	_, reply, err := ovs.MonitorAll("Open_vSwitch", nil)
 
 	if reply == nil || err != nil {
 		t.Error("Monitor operation failed with reply=", reply, " and error=", err)
@@ -522,7 +522,7 @@ func TestMonitorCancel(t *testing.T) {
 			Modify:  true,
 		}}
 
-	_, _ = ovs.Monitor("Open_vSwitch", monitorID, requests)
+	_, _ = ovs.MonitorRaw("Open_vSwitch", monitorID, requests)
 
 	err = ovs.MonitorCancel(monitorID)
 
@@ -1,6 +1,9 @@
 package libovsdb
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"reflect"
+)
 
 // Row is a table Row according to RFC7047
 type Row struct {
@@ -22,6 +25,115 @@ func (r *Row) UnmarshalJSON(b []byte) (err error) {
 	return err
 }
 
+// Equal reports whether both rows have the same columns with equal values.
+// Sets and maps are compared regardless of order and a bare atom equals a set
+// holding only that atom
+func (r Row) Equal(other Row) bool {
+	if len(r.Fields) != len(other.Fields) {
+		return false
+	}
+	for column, value := range r.Fields {
+		otherValue, ok := other.Fields[column]
+		if !ok || !valuesEqual(value, otherValue) {
+			return false
+		}
+	}
+	return true
+}
+
+// Diff returns the columns of other whose values differ from, or are missing
+// in, r. With r the current row and other the desired one, the result holds
+// what an update operation needs to send, and is empty if there is nothing to do
+func (r Row) Diff(other Row) Row {
+	diff := Row{Fields: make(map[string]interface{})}
+	for column, otherValue := range other.Fields {
+		value, ok := r.Fields[column]
+		if !ok || !valuesEqual(value, otherValue) {
+			diff.Fields[column] = otherValue
+		}
+	}
+	return diff
+}
+
+// valuesEqual compares two values in OVSDB notation
+func valuesEqual(a, b interface{}) bool {
+	setA, isSetA := asSet(a)
+	setB, isSetB := asSet(b)
+	if isSetA || isSetB {
+		if !isSetA {
+			setA = []interface{}{a}
+		}
+		if !isSetB {
+			setB = []interface{}{b}
+		}
+		return setsEqual(setA, setB)
+	}
+	mapA, isMapA := asMap(a)
+	mapB, isMapB := asMap(b)
+	if isMapA || isMapB {
+		if !isMapA || !isMapB || len(mapA) != len(mapB) {
+			return false
+		}
+		for key, valueA := range mapA {
+			valueB, ok := mapB[key]
+			if !ok || !valuesEqual(valueA, valueB) {
+				return false
+			}
+		}
+		return true
+	}
+	if _, ok := toFloat64(a); ok {
+		return atomEqual(a, b)
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func asSet(value interface{}) ([]interface{}, bool) {
+	switch v := value.(type) {
+	case OvsSet:
+		return v.GoSet, true
+	case *OvsSet:
+		if v != nil {
+			return v.GoSet, true
+		}
+	}
+	return nil, false
+}
+
+func asMap(value interface{}) (map[interface{}]interface{}, bool) {
+	switch v := value.(type) {
+	case OvsMap:
+		return v.GoMap, true
+	case *OvsMap:
+		if v != nil {
+			return v.GoMap, true
+		}
+	}
+	return nil, false
+}
+
+// setsEqual compares the elements of two sets regardless of their order
+func setsEqual(a, b []interface{}) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	matched := make([]bool, len(b))
+	for _, elemA := range a {
+		found := false
+		for i, elemB := range b {
+			if !matched[i] && valuesEqual(elemA, elemB) {
+				matched[i] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
 // ResultRow is an properly unmarshalled row returned by Transact
 type ResultRow map[string]interface{}
 
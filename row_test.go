@@ -0,0 +1,56 @@
+package libovsdb
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRowEqualAndDiff(t *testing.T) {
+	port1 := UUID{GoUUID: "550e8400-e29b-41d4-a716-446655440000"}
+	port2 := UUID{GoUUID: "550e8400-e29b-41d4-a716-446655440001"}
+	ports, _ := NewOvsSet([]UUID{port1, port2})
+	externalIDs, _ := NewOvsMap(map[string]string{"a": "1", "b": "2"})
+	current := Row{Fields: map[string]interface{}{
+		"name":         "br0",
+		"ports":        *ports,
+		"external_ids": *externalIDs,
+		"flood_vlans":  float64(10),
+		"fail_mode":    "secure",
+	}}
+
+	reordered, _ := NewOvsSet([]UUID{port2, port1})
+	failMode, _ := NewOvsSet([]string{"secure"})
+	same := Row{Fields: map[string]interface{}{
+		"name":         "br0",
+		"ports":        reordered,
+		"external_ids": OvsMap{GoMap: map[interface{}]interface{}{"b": "2", "a": "1"}},
+		"flood_vlans":  10,
+		"fail_mode":    failMode,
+	}}
+	if !current.Equal(same) || !same.Equal(current) {
+		t.Error("Expected rows to be equal")
+	}
+	if diff := current.Diff(same); len(diff.Fields) != 0 {
+		t.Errorf("Expected no diff, got %v", diff.Fields)
+	}
+
+	onePort, _ := NewOvsSet([]UUID{port1})
+	changed := Row{Fields: map[string]interface{}{
+		"name":         "br0",
+		"ports":        onePort,
+		"external_ids": OvsMap{GoMap: map[interface{}]interface{}{"a": "1", "b": "3"}},
+		"flood_vlans":  10,
+		"stp_enable":   true,
+	}}
+	if current.Equal(changed) {
+		t.Error("Expected rows to differ")
+	}
+	expected := map[string]interface{}{
+		"ports":        onePort,
+		"external_ids": changed.Fields["external_ids"],
+		"stp_enable":   true,
+	}
+	if diff := current.Diff(changed); !reflect.DeepEqual(diff.Fields, expected) {
+		t.Errorf("Expected diff %v, got %v", expected, diff.Fields)
+	}
+}
@@ -29,6 +29,16 @@ func NewMonitorArgs(database string, value interface{}, requests map[string]Moni
 	return []interface{}{database, value, requests}
 }
 
+// NewMonitorCondArgs creates a new set of arguments for a monitor_cond RPC
+func NewMonitorCondArgs(database string, value interface{}, requests map[string]MonitorCondRequest) []interface{} {
+	return []interface{}{database, value, requests}
+}
+
+// NewMonitorCondSinceArgs creates a new set of arguments for a monitor_cond_since RPC
+func NewMonitorCondSinceArgs(database string, value interface{}, requests map[string]MonitorCondRequest, lastTxnID string) []interface{} {
+	return []interface{}{database, value, requests, lastTxnID}
+}
+
 // NewMonitorCancelArgs creates a new set of arguments for a monitor_cancel RPC
 func NewMonitorCancelArgs(value interface{}) []interface{} {
 	return []interface{}{value}
@@ -33,7 +33,7 @@ func TestNewMultipleTransactArgs(t *testing.T) {
 	operation2 := Operation{Op: "delete", Table: "Bridge"}
 	args := NewTransactArgs(database, operation1, operation2)
 	argString, _ := json.Marshal(args)
-	expected := `["Open_vSwitch",{"op":"insert","table":"Bridge"},{"op":"delete","table":"Bridge"}]`
+	expected := `["Open_vSwitch",{"op":"insert","table":"Bridge"},{"where":[],"op":"delete","table":"Bridge"}]`
 	if string(argString) != expected {
 		t.Error("Expected: ", expected, " Got: ", string(argString))
 	}
@@ -72,6 +72,40 @@ func TestNewMonitorArgs(t *testing.T) {
 	}
 }
 
+func TestNewMonitorCondArgs(t *testing.T) {
+	database := "Open_vSwitch"
+	value := 1
+	r := MonitorCondRequest{
+		MonitorRequest: MonitorRequest{
+			Columns: []string{"name"},
+			Select: MonitorSelect{
+				Initial: true,
+				Modify:  true,
+			},
+		},
+		Where: [][]interface{}{NewCondition("name", "==", "br0")},
+	}
+	requests := make(map[string]MonitorCondRequest)
+	requests["Bridge"] = r
+
+	args := NewMonitorCondArgs(database, value, requests)
+	argString, _ := json.Marshal(args)
+	expected := `["Open_vSwitch",1,{"Bridge":{"columns":["name"],"select":{"initial":true,"modify":true},"where":[["name","==","br0"]]}}]`
+	if string(argString) != expected {
+		t.Error("Expected: ", expected, " Got: ", string(argString))
+	}
+}
+
+func TestNewMonitorCondSinceArgs(t *testing.T) {
+	requests := map[string]MonitorCondRequest{"Bridge": {MonitorRequest: MonitorRequest{Columns: []string{"name"}}}}
+	args := NewMonitorCondSinceArgs("Open_vSwitch", 1, requests, "txn")
+	argString, _ := json.Marshal(args)
+	expected := `["Open_vSwitch",1,{"Bridge":{"columns":["name"],"select":{}}},"txn"]`
+	if string(argString) != expected {
+		t.Error("Expected: ", expected, " Got: ", string(argString))
+	}
+}
+
 func TestNewMonitorCancelArgs(t *testing.T) {
 	value := 1
 	args := NewMonitorCancelArgs(value)
@@ -127,3 +161,55 @@ func TestUpdate(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestUpdate2(t *testing.T) {
+	var reply interface{}
+
+	// Update2 notification should fail for arrays of size < 2
+	err := update2(nil, []interface{}{"hello"}, &reply)
+	if err == nil {
+		t.Error("Expected: error for a dummy request")
+	}
+
+	// Update2 notification should fail if arg[1] is not a <table-updates2>
+	err = update2(nil, []interface{}{"hello", "gophers"}, &reply)
+	if err == nil {
+		t.Error("Expected: error for a dummy request")
+	}
+
+	validUpdate := map[string]interface{}{
+		"table": map[string]interface{}{
+			"uuid": map[string]interface{}{"delete": nil},
+		},
+	}
+	err = update2(nil, []interface{}{"hello", validUpdate}, &reply)
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestGetTableUpdatesFromRawUnmarshal2(t *testing.T) {
+	raw := []byte(`{"Bridge":{
+		"a":{"initial":{"name":"br0"}},
+		"b":{"insert":{"name":"br1"}},
+		"c":{"delete":null},
+		"d":{"modify":{"external_ids":["map",[["k","v"]]]}}}}`)
+	var rowUpdates TableUpdates2
+	if err := json.Unmarshal(raw, &rowUpdates); err != nil {
+		t.Fatal(err)
+	}
+	rows := getTableUpdatesFromRawUnmarshal2(rowUpdates).Updates["Bridge"].Rows
+
+	if rows["a"].New.Fields["name"] != "br0" || rows["a"].Old.Fields != nil {
+		t.Errorf("Unexpected initial row update %v", rows["a"])
+	}
+	if rows["b"].New.Fields["name"] != "br1" || rows["b"].Old.Fields != nil {
+		t.Errorf("Unexpected insert row update %v", rows["b"])
+	}
+	if rows["c"].New.Fields != nil || rows["c"].Old.Fields == nil {
+		t.Errorf("Unexpected delete row update %v", rows["c"])
+	}
+	if _, ok := rows["d"]; ok {
+		t.Errorf("Expected the modify row update to be left out, got %v", rows["d"])
+	}
+}
@@ -3,6 +3,12 @@ package libovsdb
 import (
 	"fmt"
 	"io"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
 )
 
 // DatabaseSchema is a database schema according to RFC7047
@@ -10,6 +16,7 @@ type DatabaseSchema struct {
 	Name    string                 `json:"name"`
 	Version string                 `json:"version"`
 	Tables  map[string]TableSchema `json:"tables"`
+	skipped []string
 }
 
 // TableSchema is a table schema according to RFC7047
@@ -26,6 +33,97 @@ type ColumnSchema struct {
 	Mutable   bool        `json:"mutable,omitempty"`
 }
 
+// Table returns the schema of the named table, or nil if there is none
+func (schema DatabaseSchema) Table(name string) *TableSchema {
+	table, ok := schema.Tables[name]
+	if !ok {
+		return nil
+	}
+	return &table
+}
+
+// GetColumn returns the schema of a column of a table. The _uuid and _version
+// columns every table has are included
+func (schema DatabaseSchema) GetColumn(table, column string) (*ColumnSchema, error) {
+	tableSchema := schema.Table(table)
+	if tableSchema == nil {
+		return nil, fmt.Errorf("unknown table %q in database %q", table, schema.Name)
+	}
+	if column == "_uuid" || column == "_version" {
+		return &ColumnSchema{Name: column, Type: "uuid"}, nil
+	}
+	columnSchema, ok := tableSchema.Columns[column]
+	if !ok {
+		return nil, fmt.Errorf("unknown column %q in table %q", column, table)
+	}
+	return &columnSchema, nil
+}
+
+// SkippedTables returns the tables of a schema fetched by the client that have
+// no columns, as can happen with a partial schema. MonitorAll leaves them out
+// and operations or monitor requests on them are rejected
+func (schema DatabaseSchema) SkippedTables() []string {
+	return schema.skipped
+}
+
+// recordTablesWithoutColumns records the tables with no columns as skipped
+func (schema *DatabaseSchema) recordTablesWithoutColumns() {
+	for name, table := range schema.Tables {
+		if len(table.Columns) == 0 {
+			schema.skipped = append(schema.skipped, name)
+		}
+	}
+	sort.Strings(schema.skipped)
+}
+
+// usableTable returns the schema of a table operations and monitor requests
+// can refer to
+func (schema DatabaseSchema) usableTable(name string) (TableSchema, error) {
+	table, ok := schema.Tables[name]
+	if !ok {
+		return table, fmt.Errorf("unknown table %q in database %q", name, schema.Name)
+	}
+	if len(table.Columns) == 0 {
+		return table, fmt.Errorf("table %q in database %q has no columns in the schema", name, schema.Name)
+	}
+	return table, nil
+}
+
+// VersionAtLeast reports whether the schema version is v or newer. Both
+// versions must be in the <major>.<minor>.<patch> format of RFC7047
+func (schema DatabaseSchema) VersionAtLeast(v string) (bool, error) {
+	current, err := parseVersion(schema.Version)
+	if err != nil {
+		return false, err
+	}
+	min, err := parseVersion(v)
+	if err != nil {
+		return false, err
+	}
+	for i := range current {
+		if current[i] != min[i] {
+			return current[i] > min[i], nil
+		}
+	}
+	return true, nil
+}
+
+func parseVersion(v string) ([3]int, error) {
+	var version [3]int
+	parts := strings.Split(v, ".")
+	if len(parts) != 3 {
+		return version, fmt.Errorf("invalid schema version %q", v)
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 || part[0] == '+' {
+			return version, fmt.Errorf("invalid schema version %q", v)
+		}
+		version[i] = n
+	}
+	return version, nil
+}
+
 // Print will print the contents of the DatabaseSchema
 func (schema DatabaseSchema) Print(w io.Writer) {
 	fmt.Fprintf(w, "%s, (%s)\n", schema.Name, schema.Version)
@@ -38,36 +136,339 @@ func (schema DatabaseSchema) Print(w io.Writer) {
 }
 
 // Basic validation for operations against Database Schema
-func (schema DatabaseSchema) validateOperations(operations ...Operation) bool {
+func (schema DatabaseSchema) validateOperations(operations ...Operation) error {
 	for _, op := range operations {
-		table, ok := schema.Tables[op.Table]
-		if ok {
-			for column := range op.Row {
-				if _, ok := table.Columns[column]; !ok {
-					if column != "_uuid" && column != "_version" {
-						return false
-					}
-				}
+		switch op.Op {
+		case "comment", "abort", "assert":
+			// these operations don't refer to a table
+			continue
+		}
+		table, err := schema.usableTable(op.Table)
+		if err != nil {
+			return err
+		}
+		if err := table.validateRow(op.Table, op.Row); err != nil {
+			return err
+		}
+		for _, row := range op.Rows {
+			if err := table.validateRow(op.Table, row); err != nil {
+				return err
 			}
-			for _, row := range op.Rows {
-				for column := range row {
-					if _, ok := table.Columns[column]; !ok {
-						if column != "_uuid" && column != "_version" {
-							return false
-						}
-					}
+		}
+		for _, column := range op.Columns {
+			if _, ok := table.Columns[column]; !ok {
+				if column != "_uuid" && column != "_version" {
+					return fmt.Errorf("unknown column %q in table %q", column, op.Table)
 				}
 			}
-			for _, column := range op.Columns {
-				if _, ok := table.Columns[column]; !ok {
-					if column != "_uuid" && column != "_version" {
-						return false
-					}
+		}
+		for _, condition := range op.Where {
+			if err := validateCondition(op.Table, condition); err != nil {
+				return err
+			}
+		}
+		if op.Op == "wait" && Function(op.Until) != FunctionEqual && Function(op.Until) != FunctionNotEqual {
+			return fmt.Errorf("invalid until %q in wait on table %q", op.Until, op.Table)
+		}
+		for _, mutation := range op.Mutations {
+			if m, ok := operator(mutation); ok && !Mutator(m).Valid() {
+				return fmt.Errorf("invalid mutator %q in mutation on table %q", m, op.Table)
+			}
+		}
+	}
+	return nil
+}
+
+// validateCondition checks the function of a condition on table
+func validateCondition(table string, condition interface{}) error {
+	if f, ok := operator(condition); ok && !Function(f).Valid() {
+		return fmt.Errorf("invalid function %q in condition on table %q", f, table)
+	}
+	return nil
+}
+
+// operator returns the function of a condition or the mutator of a mutation,
+// given as a [<column>, <operator>, <value>] slice
+func operator(clause interface{}) (string, bool) {
+	sl, ok := clause.([]interface{})
+	if !ok || len(sl) != 3 {
+		return "", false
+	}
+	switch op := sl[1].(type) {
+	case Function:
+		return string(op), true
+	case Mutator:
+		return string(op), true
+	case string:
+		return op, true
+	}
+	return "", false
+}
+
+// validateRow checks that the row columns exist and hold values of their type
+func (table TableSchema) validateRow(name string, row map[string]interface{}) error {
+	for column, value := range row {
+		columnSchema, ok := table.Columns[column]
+		if !ok {
+			if column != "_uuid" && column != "_version" {
+				return fmt.Errorf("unknown column %q in table %q", column, name)
+			}
+			continue
+		}
+		if err := columnSchema.validateValue(value); err != nil {
+			return fmt.Errorf("invalid value for column %q in table %q: %v", column, name, err)
+		}
+	}
+	return nil
+}
+
+// validateValue checks the atoms of value against the column type. Go types
+// with no OVSDB counterpart are left to the server to judge
+func (column ColumnSchema) validateValue(value interface{}) error {
+	key := baseType(column.Type, "key")
+	val := baseType(column.Type, "value")
+	switch v := value.(type) {
+	case *OvsSet:
+		if v == nil {
+			return nil
+		}
+		value = *v
+	case *OvsMap:
+		if v == nil {
+			return nil
+		}
+		value = *v
+	}
+	switch v := value.(type) {
+	case OvsSet:
+		if val != nil {
+			return fmt.Errorf("expected a map, got %T", value)
+		}
+		if err := column.validateSize(len(v.GoSet)); err != nil {
+			return err
+		}
+		return validateSet(key, v)
+	case OvsMap:
+		if val == nil {
+			return fmt.Errorf("expected %v, got %T", key["type"], value)
+		}
+		if err := column.validateSize(len(v.GoMap)); err != nil {
+			return err
+		}
+		return validateMap(key, val, v)
+	}
+	if goAtomicType(value) == "" {
+		return nil
+	}
+	if val != nil {
+		return fmt.Errorf("expected a map, got %T", value)
+	}
+	if err := column.validateSize(1); err != nil {
+		return err
+	}
+	return validateAtom(key, value)
+}
+
+// validateSize checks the number of elements of a value against the min and
+// max of the column type, which both default to 1
+func (column ColumnSchema) validateSize(size int) error {
+	min, max := 1.0, 1.0
+	if t, ok := column.Type.(map[string]interface{}); ok {
+		if n, ok := t["min"].(float64); ok {
+			min = n
+		}
+		switch n := t["max"].(type) {
+		case float64:
+			max = n
+		case string:
+			if n == "unlimited" {
+				max = math.Inf(1)
+			}
+		}
+	}
+	if float64(size) < min {
+		return fmt.Errorf("%d elements, expected at least %v", size, min)
+	}
+	if float64(size) > max {
+		return fmt.Errorf("%d elements, expected at most %v", size, max)
+	}
+	return nil
+}
+
+func validateSet(key map[string]interface{}, set OvsSet) error {
+	for _, elem := range set.GoSet {
+		if err := validateAtom(key, elem); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateMap(key, value map[string]interface{}, m OvsMap) error {
+	for k, elem := range m.GoMap {
+		if err := validateAtom(key, k); err != nil {
+			return err
+		}
+		if err := validateAtom(value, elem); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// baseType returns the <base-type> of the "key" or "value" part of a column
+// type according to RFC7047, in its object form, or nil if there is none
+func baseType(columnType interface{}, part string) map[string]interface{} {
+	switch t := columnType.(type) {
+	case string:
+		if part == "key" {
+			return map[string]interface{}{"type": t}
+		}
+	case map[string]interface{}:
+		switch base := t[part].(type) {
+		case string:
+			return map[string]interface{}{"type": base}
+		case map[string]interface{}:
+			return base
+		}
+	}
+	return nil
+}
+
+// validateAtom checks an atom against a <base-type>
+func validateAtom(base map[string]interface{}, atom interface{}) error {
+	goType := goAtomicType(atom)
+	if base == nil || goType == "" {
+		return nil
+	}
+	atomicType, _ := base["type"].(string)
+	// JSON numbers decode as float64 whatever their type
+	isNumber := func(t string) bool { return t == "integer" || t == "real" }
+	if atomicType != "" && goType != atomicType && !(isNumber(goType) && isNumber(atomicType)) {
+		return fmt.Errorf("expected %s, got %T", atomicType, atom)
+	}
+	if n, ok := toFloat64(atom); ok && atomicType == "integer" && n != math.Trunc(n) {
+		return fmt.Errorf("expected integer, got %v", atom)
+	}
+	if s, ok := atom.(string); ok {
+		if err := validateLength(base, s); err != nil {
+			return err
+		}
+	}
+	if n, ok := toFloat64(atom); ok {
+		if err := validateRange(base, atomicType, n); err != nil {
+			return err
+		}
+	}
+	if enum, ok := base["enum"]; ok {
+		values := enumValues(enum)
+		found := false
+		for _, value := range values {
+			if atomEqual(atom, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%v is not one of %v", atom, values)
+		}
+	}
+	return nil
+}
+
+// validateLength checks a string against the minLength and maxLength of its
+// base type, counted in characters as ovsdb-server does. Zero means unbounded
+func validateLength(base map[string]interface{}, s string) error {
+	length := utf8.RuneCountInString(s)
+	if min, _ := base["minLength"].(float64); min > 0 && float64(length) < min {
+		return fmt.Errorf("string %q has length %d, expected at least %v", s, length, min)
+	}
+	if max, _ := base["maxLength"].(float64); max > 0 && float64(length) > max {
+		return fmt.Errorf("string %q has length %d, expected at most %v", s, length, max)
+	}
+	return nil
+}
+
+// validateRange checks a number against the minInteger and maxInteger, or
+// minReal and maxReal, of its base type. Missing bounds mean unbounded
+func validateRange(base map[string]interface{}, atomicType string, n float64) error {
+	minKey, maxKey := "minInteger", "maxInteger"
+	if atomicType == "real" {
+		minKey, maxKey = "minReal", "maxReal"
+	}
+	if min, ok := base[minKey].(float64); ok && n < min {
+		return fmt.Errorf("%v is less than %s %v", n, minKey, min)
+	}
+	if max, ok := base[maxKey].(float64); ok && n > max {
+		return fmt.Errorf("%v is greater than %s %v", n, maxKey, max)
+	}
+	return nil
+}
+
+// enumValues returns the values of an enum, which is either a single atom or
+// a set in OVSDB notation
+func enumValues(enum interface{}) []interface{} {
+	if set, ok := enum.([]interface{}); ok && len(set) == 2 && set[0] == "set" {
+		if values, ok := set[1].([]interface{}); ok {
+			return values
+		}
+	}
+	return []interface{}{enum}
+}
+
+func atomEqual(a, b interface{}) bool {
+	if x, ok := toFloat64(a); ok {
+		y, ok := toFloat64(b)
+		return ok && x == y
+	}
+	return a == b
+}
+
+func toFloat64(atom interface{}) (float64, bool) {
+	v := reflect.ValueOf(atom)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+	return 0, false
+}
+
+// goAtomicType returns the atomic type a Go value stands for, or "" if it is
+// not an atom
+func goAtomicType(atom interface{}) string {
+	switch atom.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return "integer"
+	case float32, float64:
+		return "real"
+	case UUID, *UUID:
+		return "uuid"
+	}
+	return ""
+}
+
+// validateMonitorRequests checks that the monitored tables and columns exist
+func (schema DatabaseSchema) validateMonitorRequests(requests map[string]MonitorRequest) error {
+	for table, request := range requests {
+		tableSchema, err := schema.usableTable(table)
+		if err != nil {
+			return err
+		}
+		for _, column := range request.Columns {
+			if _, ok := tableSchema.Columns[column]; !ok {
+				if column != "_uuid" && column != "_version" {
+					return fmt.Errorf("unknown column %q in table %q", column, table)
 				}
 			}
-		} else {
-			return false
 		}
 	}
-	return true
+	return nil
 }
@@ -0,0 +1,322 @@
+package libovsdb
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// bridgeSchema returns a schema with a single Bridge table made of columns
+func bridgeSchema(t *testing.T, columns string) DatabaseSchema {
+	var schema DatabaseSchema
+	err := json.Unmarshal([]byte(`{
+	  "name": "Open_vSwitch",
+	  "tables": {"Bridge": {"columns": {`+columns+`}}}
+	}`), &schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return schema
+}
+
+// validateBridgeRows validates an insert of each row, checking that the
+// invalid ones fail with an error naming their column
+func validateBridgeRows(t *testing.T, schema DatabaseSchema, valid []map[string]interface{}, invalid map[string]map[string]interface{}) {
+	for _, row := range valid {
+		op := Operation{Op: "insert", Table: "Bridge", Row: row}
+		if err := schema.validateOperations(op); err != nil {
+			t.Errorf("Unexpected error validating %v: %v", row, err)
+		}
+	}
+	for column, row := range invalid {
+		op := Operation{Op: "insert", Table: "Bridge", Rows: []map[string]interface{}{row}}
+		err := schema.validateOperations(op)
+		if err == nil || !strings.Contains(err.Error(), `"`+column+`"`) {
+			t.Errorf("Expected an error naming column %s for %v, got %v", column, row, err)
+		}
+	}
+}
+
+func TestValidateOperationsTypes(t *testing.T) {
+	schema := bridgeSchema(t, `
+	  "name": {"type": "string"},
+	  "stp_enable": {"type": "boolean"},
+	  "mcast_snooping_enable": {"type": {"key": {"type": "boolean"}, "min": 0, "max": 1}},
+	  "ports": {"type": {"key": {"type": "uuid", "refTable": "Port"}, "min": 0, "max": "unlimited"}},
+	  "external_ids": {"type": {"key": "string", "value": "string", "min": 0, "max": "unlimited"}},
+	  "flood_vlans": {"type": {"key": {"type": "integer"}, "min": 0, "max": 4096}}`)
+
+	ports, _ := NewOvsSet([]UUID{{GoUUID: "port"}})
+	vlans, _ := NewOvsSet([]int{1, 2})
+	externalIDs, _ := NewOvsMap(map[string]string{"key": "value"})
+	badIDs, _ := NewOvsMap(map[string]int{"key": 1})
+	badPorts, _ := NewOvsSet([]string{"port"})
+
+	valid := []map[string]interface{}{
+		{"name": "br0", "stp_enable": true},
+		{"mcast_snooping_enable": false},
+		{"ports": ports, "external_ids": externalIDs, "flood_vlans": vlans},
+		{"flood_vlans": 10, "ports": UUID{GoUUID: "port"}},
+		{"flood_vlans": float64(10)},
+	}
+	validateBridgeRows(t, schema, valid, map[string]map[string]interface{}{
+		"name":         {"name": 1},
+		"stp_enable":   {"stp_enable": "true"},
+		"ports":        {"ports": badPorts},
+		"external_ids": {"external_ids": badIDs},
+		"flood_vlans":  {"flood_vlans": "10"},
+		"nmae":         {"nmae": "br0"},
+	})
+
+	if err := schema.validateOperations(Operation{Op: "insert", Table: "Brige"}); err == nil {
+		t.Error("Expected an error for an unknown table")
+	}
+	if err := schema.validateOperations(NewCommentOperation("comment"), NewAbortOperation(), NewAssertOperation("lock")); err != nil {
+		t.Errorf("Unexpected error validating operations without a table: %v", err)
+	}
+}
+
+func TestValidateOperationsNumbersAndMaps(t *testing.T) {
+	schema := bridgeSchema(t, `
+	  "priority": {"type": "integer"},
+	  "ratio": {"type": "real"},
+	  "flood_vlans": {"type": {"key": "integer", "min": 0, "max": 4096}},
+	  "external_ids": {"type": {"key": "string", "value": "string", "min": 0, "max": "unlimited"}}`)
+
+	vlans, _ := NewOvsSet([]float64{1, 2})
+	badVlans, _ := NewOvsSet([]float64{1, 2.5})
+	ids, _ := NewOvsSet([]string{"key"})
+	externalIDs, _ := NewOvsMap(map[string]string{"key": "value"})
+
+	tests := []struct {
+		row   map[string]interface{}
+		valid bool
+	}{
+		{map[string]interface{}{"priority": float64(3)}, true},
+		{map[string]interface{}{"priority": 3.5}, false},
+		{map[string]interface{}{"priority": float32(-0.5)}, false},
+		{map[string]interface{}{"ratio": 3.5}, true},
+		{map[string]interface{}{"flood_vlans": vlans}, true},
+		{map[string]interface{}{"flood_vlans": badVlans}, false},
+		{map[string]interface{}{"external_ids": externalIDs}, true},
+		{map[string]interface{}{"external_ids": ids}, false},
+		{map[string]interface{}{"external_ids": *ids}, false},
+		{map[string]interface{}{"flood_vlans": externalIDs}, false},
+	}
+	for _, test := range tests {
+		err := schema.validateOperations(Operation{Op: "insert", Table: "Bridge", Row: test.row})
+		if test.valid && err != nil {
+			t.Errorf("Unexpected error validating %v: %v", test.row, err)
+		}
+		if !test.valid && err == nil {
+			t.Errorf("Expected an error validating %v", test.row)
+		}
+	}
+}
+
+func TestValidateOperationsEnum(t *testing.T) {
+	schema := bridgeSchema(t, `
+	  "fail_mode": {"type": {"key": {"type": "string", "enum": ["set", ["standalone", "secure"]]}, "min": 0, "max": 1}},
+	  "protocols": {"type": {"key": {"type": "string", "enum": ["set", ["OpenFlow10", "OpenFlow13"]]}, "min": 0, "max": "unlimited"}},
+	  "datapath_type": {"type": {"key": {"type": "string", "enum": "system"}}},
+	  "priority": {"type": {"key": {"type": "integer", "enum": ["set", [1, 2]]}}}`)
+
+	protocols, _ := NewOvsSet([]string{"OpenFlow10", "OpenFlow13"})
+	badProtocols, _ := NewOvsSet([]string{"OpenFlow10", "OpenFlow15"})
+	valid := []map[string]interface{}{
+		{"fail_mode": "secure", "protocols": protocols},
+		{"datapath_type": "system", "priority": 2},
+		{"priority": float64(1)},
+	}
+	validateBridgeRows(t, schema, valid, map[string]map[string]interface{}{
+		"fail_mode":     {"fail_mode": "insecure"},
+		"protocols":     {"protocols": badProtocols},
+		"datapath_type": {"datapath_type": "netdev"},
+		"priority":      {"priority": 3},
+	})
+
+	op := Operation{Op: "insert", Table: "Bridge", Row: map[string]interface{}{"fail_mode": "insecure"}}
+	if err := schema.validateOperations(op); err == nil || !strings.Contains(err.Error(), "standalone secure") {
+		t.Errorf("Expected an error listing the allowed values, got %v", err)
+	}
+}
+
+func TestValidateOperationsLength(t *testing.T) {
+	schema := bridgeSchema(t, `
+	  "name": {"type": {"key": {"type": "string", "minLength": 2, "maxLength": 4}}},
+	  "description": {"type": {"key": {"type": "string", "maxLength": 3}, "min": 0, "max": "unlimited"}},
+	  "external_ids": {"type": {"key": {"type": "string", "minLength": 1}, "value": "string", "min": 0, "max": "unlimited"}},
+	  "comment": {"type": {"key": {"type": "string", "minLength": 0, "maxLength": 0}}}`)
+
+	description, _ := NewOvsSet([]string{"a", "abc"})
+	badDescription, _ := NewOvsSet([]string{"a", "abcd"})
+	externalIDs, _ := NewOvsMap(map[string]string{"k": ""})
+	badIDs, _ := NewOvsMap(map[string]string{"": "v"})
+	valid := []map[string]interface{}{
+		{"name": "br", "description": description, "external_ids": externalIDs},
+		{"name": "br00", "comment": "any length goes"},
+		{"name": "brü"},
+	}
+	validateBridgeRows(t, schema, valid, map[string]map[string]interface{}{
+		"name":         {"name": "b"},
+		"description":  {"description": badDescription},
+		"external_ids": {"external_ids": badIDs},
+	})
+
+	op := Operation{Op: "insert", Table: "Bridge", Row: map[string]interface{}{"name": "bridge"}}
+	if err := schema.validateOperations(op); err == nil || !strings.Contains(err.Error(), "length 6, expected at most 4") {
+		t.Errorf("Expected an error with the length and the bound, got %v", err)
+	}
+}
+
+func TestValidateOperationsRange(t *testing.T) {
+	schema := bridgeSchema(t, `
+	  "flood_vlans": {"type": {"key": {"type": "integer", "minInteger": 0, "maxInteger": 4095}, "min": 0, "max": 4096}},
+	  "priority": {"type": {"key": {"type": "integer", "maxInteger": 10}}},
+	  "ratio": {"type": {"key": {"type": "real", "minReal": 0.5, "maxReal": 1.5}}},
+	  "other_config": {"type": {"key": "string", "value": {"type": "integer", "minInteger": 1}, "min": 0, "max": "unlimited"}}`)
+
+	vlans, _ := NewOvsSet([]int{0, 4095})
+	badVlans, _ := NewOvsSet([]int{1, 4096})
+	otherConfig, _ := NewOvsMap(map[string]int{"k": 1})
+	badConfig, _ := NewOvsMap(map[string]int{"k": 0})
+	valid := []map[string]interface{}{
+		{"flood_vlans": vlans, "other_config": otherConfig},
+		{"flood_vlans": 0, "priority": -100, "ratio": 0.5},
+		{"flood_vlans": uint16(4095), "priority": 10, "ratio": 1.5},
+	}
+	validateBridgeRows(t, schema, valid, map[string]map[string]interface{}{
+		"flood_vlans":  {"flood_vlans": badVlans},
+		"priority":     {"priority": 11},
+		"ratio":        {"ratio": 0.49},
+		"other_config": {"other_config": badConfig},
+	})
+
+	op := Operation{Op: "insert", Table: "Bridge", Row: map[string]interface{}{"flood_vlans": -1}}
+	if err := schema.validateOperations(op); err == nil || !strings.Contains(err.Error(), "-1 is less than minInteger 0") {
+		t.Errorf("Expected an error with the value and the bound, got %v", err)
+	}
+}
+
+func TestValidateOperationsSize(t *testing.T) {
+	schema := bridgeSchema(t, `
+	  "name": {"type": "string"},
+	  "fail_mode": {"type": {"key": "string", "min": 0, "max": 1}},
+	  "controller": {"type": {"key": "string", "min": 1, "max": 2}},
+	  "ports": {"type": {"key": "string", "min": 0, "max": "unlimited"}},
+	  "external_ids": {"type": {"key": "string", "value": "string", "min": 0, "max": 1}}`)
+
+	newSet := func(s []string) *OvsSet {
+		set, _ := NewOvsSet(s)
+		return set
+	}
+	newMap := func(m map[string]string) *OvsMap {
+		ovsMap, _ := NewOvsMap(m)
+		return ovsMap
+	}
+	valid := []map[string]interface{}{
+		{"name": "br0", "fail_mode": newSet([]string{}), "controller": "c1"},
+		{"name": newSet([]string{"br0"}), "fail_mode": "secure", "controller": newSet([]string{"c1", "c2"})},
+		{"ports": newSet([]string{"p1", "p2", "p3"}), "external_ids": newMap(map[string]string{})},
+	}
+	validateBridgeRows(t, schema, valid, map[string]map[string]interface{}{
+		"name":         {"name": newSet([]string{})},
+		"fail_mode":    {"fail_mode": newSet([]string{"standalone", "secure"})},
+		"controller":   {"controller": newSet([]string{"c1", "c2", "c3"})},
+		"external_ids": {"external_ids": newMap(map[string]string{"a": "1", "b": "2"})},
+	})
+
+	op := Operation{Op: "insert", Table: "Bridge", Row: map[string]interface{}{"controller": newSet([]string{})}}
+	if err := schema.validateOperations(op); err == nil || !strings.Contains(err.Error(), "0 elements, expected at least 1") {
+		t.Errorf("Expected an error with the violated bound, got %v", err)
+	}
+}
+
+func TestSchemaTableAndGetColumn(t *testing.T) {
+	schema := bridgeSchema(t, `"name": {"type": "string"}`)
+
+	if table := schema.Table("Bridge"); table == nil || len(table.Columns) != 1 {
+		t.Errorf("Expected the Bridge table, got %v", table)
+	}
+	if table := schema.Table("Port"); table != nil {
+		t.Errorf("Expected no Port table, got %v", table)
+	}
+
+	column, err := schema.GetColumn("Bridge", "name")
+	if err != nil || column.Type != "string" {
+		t.Errorf("Expected the name column, got %v, %v", column, err)
+	}
+	column, err = schema.GetColumn("Bridge", "_uuid")
+	if err != nil || column.Type != "uuid" {
+		t.Errorf("Expected the _uuid column, got %v, %v", column, err)
+	}
+	if _, err := schema.GetColumn("Port", "name"); err == nil || !strings.Contains(err.Error(), `"Port"`) {
+		t.Errorf("Expected an error naming the missing table, got %v", err)
+	}
+	if _, err := schema.GetColumn("Bridge", "nmae"); err == nil || !strings.Contains(err.Error(), `"nmae"`) {
+		t.Errorf("Expected an error naming the missing column, got %v", err)
+	}
+}
+
+func TestVersionAtLeast(t *testing.T) {
+	schema := DatabaseSchema{Version: "8.2.0"}
+	tests := []struct {
+		version  string
+		expected bool
+	}{
+		{"8.2.0", true},
+		{"8.1.9", true},
+		{"7.16.1", true},
+		{"8.2.1", false},
+		{"8.10.0", false},
+		{"9.0.0", false},
+	}
+	for _, test := range tests {
+		ok, err := schema.VersionAtLeast(test.version)
+		if err != nil {
+			t.Errorf("Unexpected error comparing with %s: %v", test.version, err)
+		}
+		if ok != test.expected {
+			t.Errorf("Expected VersionAtLeast(%s) to be %v", test.version, test.expected)
+		}
+	}
+
+	for _, version := range []string{"8.2", "8.2.0.1", "8.x.0", "", "8.-2.0", "8.+2.0"} {
+		if _, err := schema.VersionAtLeast(version); err == nil {
+			t.Errorf("Expected an error comparing with %q", version)
+		}
+	}
+	if _, err := (DatabaseSchema{Version: "8"}).VersionAtLeast("8.2.0"); err == nil {
+		t.Error("Expected an error for a malformed schema version")
+	}
+}
+
+func TestValidateOperationsOperators(t *testing.T) {
+	schema := bridgeSchema(t, `"name": {"type": "string"}, "flood_vlans": {"type": {"key": "integer", "min": 0, "max": 4096}}`)
+
+	valid := []Operation{
+		{Op: "select", Table: "Bridge", Where: []interface{}{NewCondition("name", "==", "br0")}},
+		{Op: "select", Table: "Bridge", Where: []interface{}{[]interface{}{"name", "!=", "br0"}}},
+		{Op: "mutate", Table: "Bridge", Mutations: []interface{}{NewMutation("flood_vlans", "insert", 10)}},
+		NewWaitOperation("Bridge", 0, "==", []string{"name"}, nil, NewCondition("name", "==", "br0")),
+		NewWaitOperation("Bridge", 100, "!=", []string{"name"}, []map[string]interface{}{{"name": "br0"}}),
+	}
+	for _, op := range valid {
+		if err := schema.validateOperations(op); err != nil {
+			t.Errorf("Unexpected error validating %v: %v", op, err)
+		}
+	}
+
+	invalid := []Operation{
+		{Op: "select", Table: "Bridge", Where: []interface{}{NewCondition("name", "=", "br0")}},
+		{Op: "select", Table: "Bridge", Where: []interface{}{[]interface{}{"name", "like", "br0"}}},
+		{Op: "mutate", Table: "Bridge", Mutations: []interface{}{NewMutation("flood_vlans", "+", 10)}},
+		NewWaitOperation("Bridge", 0, "<", []string{"name"}, nil),
+		{Op: "wait", Table: "Bridge", Columns: []string{"name"}},
+	}
+	for _, op := range invalid {
+		if err := schema.validateOperations(op); err == nil {
+			t.Errorf("Expected an error validating %v", op)
+		}
+	}
+}
@@ -41,6 +41,23 @@ func (o OvsSet) MarshalJSON() ([]byte, error) {
 
 // UnmarshalJSON will unmarshal a JSON byte array to an OVSDB style set
 func (o *OvsSet) UnmarshalJSON(b []byte) (err error) {
+	var value interface{}
+	if err = json.Unmarshal(b, &value); err != nil {
+		return err
+	}
+	if value == nil {
+		return nil
+	}
+	// A set with exactly one element may be sent as that bare <atom>
+	sl, ok := value.([]interface{})
+	if !ok || (len(sl) == 2 && (sl[0] == "uuid" || sl[0] == "named-uuid")) {
+		atom, err := ovsSliceToGoNotation(value)
+		if err != nil {
+			return err
+		}
+		o.GoSet = []interface{}{atom}
+		return nil
+	}
 	var oSet []interface{}
 	if err = json.Unmarshal(b, &oSet); err == nil && len(oSet) > 1 {
 		innerSet := oSet[1].([]interface{})
@@ -0,0 +1,76 @@
+package libovsdb
+
+import "fmt"
+
+// TransactionBuilder builds the operations of a transaction. It names the
+// rows it inserts so that the operations added afterwards can refer to them
+type TransactionBuilder struct {
+	operations []Operation
+	inserts    int
+}
+
+// NewTransactionBuilder returns an empty TransactionBuilder
+func NewTransactionBuilder() *TransactionBuilder {
+	return &TransactionBuilder{}
+}
+
+// Insert adds the insertion of row into table. The returned UUID refers to
+// the new row in the rows, conditions and mutations of later operations
+func (b *TransactionBuilder) Insert(table string, row map[string]interface{}) UUID {
+	b.inserts++
+	name := fmt.Sprintf("row%d", b.inserts)
+	b.operations = append(b.operations, Operation{Op: "insert", Table: table, Row: row, UUIDName: name})
+	return UUID{GoUUID: name}
+}
+
+// InsertIfAbsent adds the insertion of row into table like Insert, preceded
+// by a wait that makes the whole transaction fail if a row with the same
+// values in the index columns already exists. Those columns must be set in row
+func (b *TransactionBuilder) InsertIfAbsent(table string, row map[string]interface{}, index ...string) UUID {
+	where := make([][]interface{}, 0, len(index))
+	for _, column := range index {
+		where = append(where, NewCondition(column, string(FunctionEqual), row[column]))
+	}
+	b.operations = append(b.operations, NewWaitOperation(table, 0, string(FunctionEqual), index, nil, where...))
+	return b.Insert(table, row)
+}
+
+// Update adds the update of the columns in row for the rows of table matching
+// every condition in where, or for every row if there is none
+func (b *TransactionBuilder) Update(table string, row map[string]interface{}, where ...[]interface{}) {
+	b.operations = append(b.operations, Operation{Op: "update", Table: table, Row: row, Where: conditions(where)})
+}
+
+// Mutate adds mutations, made with NewMutation, of the rows of table matching
+// every condition in where, or of every row if there is none
+func (b *TransactionBuilder) Mutate(table string, mutations [][]interface{}, where ...[]interface{}) {
+	muts := make([]interface{}, 0, len(mutations))
+	for _, mutation := range mutations {
+		muts = append(muts, mutation)
+	}
+	b.operations = append(b.operations, Operation{Op: "mutate", Table: table, Mutations: muts, Where: conditions(where)})
+}
+
+// Delete adds the deletion of the rows of table matching every condition in
+// where, or of every row if there is none
+func (b *TransactionBuilder) Delete(table string, where ...[]interface{}) {
+	b.operations = append(b.operations, Operation{Op: "delete", Table: table, Where: conditions(where)})
+}
+
+// Add adds any other operation
+func (b *TransactionBuilder) Add(operation Operation) {
+	b.operations = append(b.operations, operation)
+}
+
+// Build returns the operations in the order they were added, ready for Transact
+func (b *TransactionBuilder) Build() []Operation {
+	return append([]Operation(nil), b.operations...)
+}
+
+func conditions(where [][]interface{}) []interface{} {
+	conds := make([]interface{}, 0, len(where))
+	for _, condition := range where {
+		conds = append(conds, condition)
+	}
+	return conds
+}
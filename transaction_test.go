@@ -0,0 +1,90 @@
+package libovsdb
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestTransactionBuilder(t *testing.T) {
+	b := NewTransactionBuilder()
+	bridge := b.Insert("Bridge", map[string]interface{}{"name": "br0"})
+	bridges, err := NewOvsSet([]UUID{bridge})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.Mutate("Open_vSwitch",
+		[][]interface{}{NewMutation("bridges", "insert", bridges)},
+		NewCondition("_uuid", "!=", UUID{GoUUID: "2f77b348-9768-4866-b761-89d5177ecdab"}))
+	b.Insert("Port", map[string]interface{}{"name": "br0"})
+
+	ops := b.Build()
+	if len(ops) != 3 {
+		t.Fatalf("expected 3 operations, got %d", len(ops))
+	}
+	if ops[0].UUIDName != "row1" || ops[2].UUIDName != "row2" {
+		t.Errorf("unexpected uuid-names %q and %q", ops[0].UUIDName, ops[2].UUIDName)
+	}
+
+	data, err := json.Marshal(ops[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"where":[["_uuid","!=",["uuid","2f77b348-9768-4866-b761-89d5177ecdab"]]],"op":"mutate","table":"Open_vSwitch","mutations":[["bridges","insert",["set",[["named-uuid","row1"]]]]]}`
+	if string(data) != expected {
+		t.Errorf("expected %s, got %s", expected, data)
+	}
+
+	b.Delete("Bridge", NewCondition("name", "==", "br0"))
+	if len(ops) != 3 {
+		t.Error("Build should return a copy of the operations")
+	}
+}
+
+func TestTransactionBuilderWithoutConditions(t *testing.T) {
+	b := NewTransactionBuilder()
+	b.Update("Bridge", map[string]interface{}{"stp_enable": true})
+	mutations := [][]interface{}{NewMutation("flood_vlans", "insert", 10)}
+	b.Mutate("Bridge", mutations)
+	b.Delete("Bridge")
+
+	expected := []string{
+		`{"where":[],"op":"update","table":"Bridge","row":{"stp_enable":true}}`,
+		`{"where":[],"op":"mutate","table":"Bridge","mutations":[["flood_vlans","insert",10]]}`,
+		`{"where":[],"op":"delete","table":"Bridge"}`,
+	}
+	for i, op := range b.Build() {
+		data, err := json.Marshal(op)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != expected[i] {
+			t.Errorf("expected %s, got %s", expected[i], data)
+		}
+	}
+}
+
+func TestTransactionBuilderInsertIfAbsent(t *testing.T) {
+	b := NewTransactionBuilder()
+	bridge := b.InsertIfAbsent("Bridge", map[string]interface{}{"name": "br0", "stp_enable": true}, "name")
+	if bridge.GoUUID != "row1" {
+		t.Errorf("expected the insert to be named row1, got %s", bridge.GoUUID)
+	}
+
+	expected := []string{
+		`{"where":[["name","==","br0"]],"rows":[],"timeout":0,"op":"wait","table":"Bridge","columns":["name"],"until":"=="}`,
+		`{"op":"insert","table":"Bridge","row":{"name":"br0","stp_enable":true},"uuid-name":"row1"}`,
+	}
+	ops := b.Build()
+	if len(ops) != len(expected) {
+		t.Fatalf("expected %d operations, got %d", len(expected), len(ops))
+	}
+	for i, op := range ops {
+		data, err := json.Marshal(op)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != expected[i] {
+			t.Errorf("expected %s, got %s", expected[i], data)
+		}
+	}
+}